@@ -2,12 +2,15 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"path/filepath"
+	_ "time/tzdata"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/scheduler"
 	"github.com/tckz/ebschedule"
@@ -18,6 +21,11 @@ var myName = filepath.Base(os.Args[0])
 
 func main() {
 	if err := run(); err != nil {
+		var exitCodeErr *ebschedule.ExitCodeError
+		if errors.As(err, &exitCodeErr) {
+			log.Printf("%v", err)
+			os.Exit(exitCodeErr.Code)
+		}
 		log.Fatalf("%v", err)
 	}
 }
@@ -26,7 +34,11 @@ func run() error {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(os.Getenv("AWS_REGION")))
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(os.Getenv("AWS_REGION")),
+		// Retries are done by ebschedule according to --max-attempts, so those of the SDK are disabled.
+		config.WithRetryer(func() aws.Retryer { return aws.NopRetryer{} }),
+	)
 	if err != nil {
 		return fmt.Errorf("config.LoadDefaultConfig: %w", err)
 	}
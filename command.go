@@ -4,20 +4,69 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/scheduler"
 	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
 	"github.com/kayac/go-config"
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 )
 
 const (
-	OptSchedule            = "schedule"
-	OptCreateScheduleGroup = "create-schedule-group"
+	OptSchedule             = "schedule"
+	OptCreateScheduleGroup  = "create-schedule-group"
+	OptPrune                = "prune"
+	OptName                 = "name"
+	OptGroup                = "group"
+	OptDeleteScheduleGroup  = "delete-schedule-group"
+	OptYes                  = "yes"
+	OptOut                  = "out"
+	OptDir                  = "dir"
+	OptAllGroups            = "all-groups"
+	OptNamePrefix           = "name-prefix"
+	OptOutput               = "output"
+	OptDryRun               = "dry-run"
+	OptExitCode             = "exit-code"
+	OptRateLimit            = "rate-limit"
+	OptConcurrency          = "concurrency"
+	OptMaxAttempts          = "max-attempts"
+	OptCount                = "count"
+	OptStrict               = "strict"
+	OptOverlay              = "overlay"
+	OptEnv                  = "env"
+	OptIgnorePath           = "ignore-path"
+	OptExpectUnchangedSince = "expect-unchanged-since"
+	OptHistoryDir           = "history-dir"
+	OptSnapshot             = "snapshot"
 )
 
+const (
+	// ExitCodeDiffFound is the exit code when differences are found with --exit-code.
+	ExitCodeDiffFound = 2
+)
+
+// ExitCodeError is an error which requests the process to exit with Code.
+type ExitCodeError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitCodeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitCodeError) Unwrap() error {
+	return e.Err
+}
+
 type CommandInput struct {
 	AppName         string
 	Version         string
@@ -26,13 +75,26 @@ type CommandInput struct {
 }
 
 func NewCommand(in *CommandInput) *cobra.Command {
+	// SchedulerClient is wrapped according to the global flags, so keep the caller's one intact.
+	in = lo.ToPtr(*in)
+	baseClient := in.SchedulerClient
+
 	root := wrapCobra(&cobra.Command{
 		Use:           in.AppName,
 		Short:         "update/diff schedule of Amazon EventBridge Scheduler",
 		SilenceErrors: true,
 		SilenceUsage:  true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			optRateLimit, _ := cmd.Flags().GetFloat64(OptRateLimit)
+			optMaxAttempts, _ := cmd.Flags().GetInt(OptMaxAttempts)
+			// Each attempt of retries waits for the rate limiter.
+			in.SchedulerClient = newRetryClient(newRateLimitedClient(baseClient, optRateLimit), optMaxAttempts)
+			return nil
+		},
 	}, func(cmd *cobra.Command) {
 		cmd.SetOut(os.Stderr)
+		cmd.PersistentFlags().Float64(OptRateLimit, 10, "maximum number of API requests per second. 0 means unlimited")
+		cmd.PersistentFlags().Int(OptMaxAttempts, 5, "maximum number of attempts of each API call on throttling and transient errors")
 	})
 
 	wrapCobra(&cobra.Command{
@@ -48,6 +110,16 @@ func NewCommand(in *CommandInput) *cobra.Command {
 
 	root.AddCommand(newUpdateCommand(in))
 	root.AddCommand(newDiffCommand(in))
+	root.AddCommand(newDeleteCommand(in))
+	root.AddCommand(newExportCommand(in))
+	root.AddCommand(newListCommand(in))
+	root.AddCommand(newPlanCommand(in))
+	root.AddCommand(newApplyCommand(in))
+	root.AddCommand(newNextCommand(in))
+	root.AddCommand(newStatusCommand(in))
+	root.AddCommand(newRollbackCommand(in))
+	root.AddCommand(newEnableCommand(in))
+	root.AddCommand(newDisableCommand(in))
 
 	return root
 }
@@ -74,15 +146,112 @@ func marshalYAML(s any) ([]byte, error) {
 	return b, nil
 }
 
-func prepareInputSchedule(fn string) (*scheduler.CreateScheduleInput, error) {
+// loadOptions controls how schedule.yaml is read.
+type loadOptions struct {
+	// Strict rejects unknown, duplicated and missing required fields.
+	Strict bool
+	// Overlays are applied to every schedule.
+	Overlays []string
+	// Env selects path/to/schedule.<Env>.overlay.yml for path/to/schedule.yml, which is applied before Overlays.
+	Env string
+	// IgnorePaths are added to the ones specified in each schedule.yaml.
+	IgnorePaths []string
+	// ExcludeDirs are not searched for schedules, such as --history-dir. Hidden directories are always excluded.
+	ExcludeDirs []string
+}
+
+func loadOptionsFromFlags(cmd *cobra.Command) loadOptions {
+	optStrict, _ := cmd.Flags().GetBool(OptStrict)
+	optOverlays, _ := cmd.Flags().GetStringArray(OptOverlay)
+	optEnv, _ := cmd.Flags().GetString(OptEnv)
+	// It is not defined for the commands which do not compare schedules.
+	optIgnorePaths, _ := cmd.Flags().GetStringArray(OptIgnorePath)
+	opts := loadOptions{Strict: optStrict, Overlays: optOverlays, Env: optEnv, IgnorePaths: optIgnorePaths}
+	// Snapshots in the history must not be read as local schedules.
+	if optHistoryDir, _ := cmd.Flags().GetString(OptHistoryDir); optHistoryDir != "" {
+		opts.ExcludeDirs = append(opts.ExcludeDirs, optHistoryDir)
+	}
+	return opts
+}
+
+// prepareInputSchedule reads schedules from fn, which may contain multiple documents separated by "---".
+func prepareInputSchedule(fn string, opts loadOptions) ([]*inputSchedule, error) {
+	overlays, err := readOverlays(fn, opts)
+	if err != nil {
+		return nil, fmt.Errorf("readOverlays: %w", err)
+	}
+
 	b, err := config.ReadWithEnv(fn)
 	if err != nil {
 		return nil, err
 	}
+	docs, err := parseYAMLDocuments(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no schedule is defined")
+	}
+
+	var ret []*inputSchedule
+	for _, doc := range docs {
+		sch, err := prepareScheduleDocument(doc.Body, overlays, opts)
+		if err != nil {
+			if len(docs) > 1 {
+				return nil, fmt.Errorf("document[%d]: %w", doc.Index, err)
+			}
+			return nil, err
+		}
+		sch.FileName = fn
+		ret = append(ret, sch)
+	}
+	return ret, nil
+}
+
+func prepareScheduleDocument(n ast.Node, overlays []*overlay, opts loadOptions) (*inputSchedule, error) {
+	if opts.Strict {
+		if len(overlays) == 0 {
+			if err := validateScheduleYAML(n); err != nil {
+				return nil, fmt.Errorf("validateScheduleYAML: %w", err)
+			}
+		} else {
+			// Required fields may be supplied by overlays, so they are checked after applying overlays.
+			// Fields are checked here too to report their positions in schedule.yaml.
+			if err := validateScheduleFields(n); err != nil {
+				return nil, fmt.Errorf("validateScheduleFields: %w", err)
+			}
+		}
+	}
+
+	js, err := documentToJSON(n)
+	if err != nil {
+		return nil, fmt.Errorf("documentToJSON: %w", err)
+	}
+
+	if len(overlays) > 0 {
+		js, err = applyOverlays(js, overlays)
+		if err != nil {
+			return nil, fmt.Errorf("applyOverlays: %w", err)
+		}
+		if opts.Strict {
+			if err := validateMergedSchedule(js); err != nil {
+				return nil, fmt.Errorf("validateMergedSchedule: %w", err)
+			}
+		}
+	}
+
+	js, settings, err := extractScheduleSettings(js)
+	if err != nil {
+		return nil, fmt.Errorf("extractScheduleSettings: %w", err)
+	}
+	ignorePaths := append(slices.Clone(opts.IgnorePaths), settings.IgnorePaths...)
+	if err := validateIgnorePaths(ignorePaths); err != nil {
+		return nil, err
+	}
 
 	var sch scheduler.CreateScheduleInput
-	if err := unmarshalYAML(b, &sch); err != nil {
-		return nil, fmt.Errorf("unmarshalYAML: %w", err)
+	if err := unmarshalScheduleJSON(js, &sch); err != nil {
+		return nil, fmt.Errorf("unmarshalScheduleJSON: %w", err)
 	}
 	if sch.GroupName == nil {
 		sch.GroupName = aws.String("default")
@@ -90,22 +259,206 @@ func prepareInputSchedule(fn string) (*scheduler.CreateScheduleInput, error) {
 	if sch.Name == nil {
 		return nil, fmt.Errorf("Name must be specified")
 	}
+	if sch.ScheduleExpression != nil {
+		if _, err := parseScheduleExpression(*sch.ScheduleExpression); err != nil {
+			return nil, fmt.Errorf("ScheduleExpression: %w", err)
+		}
+	}
 
-	return &sch, nil
+	return &inputSchedule{Input: &sch, IgnorePaths: ignorePaths}, nil
 }
 
-func unmarshalYAML(b []byte, out any) error {
-	// yaml.Unmarshal which compliant with encoding/yaml with types without yaml tag such as CreateScheduleInput assumes all keys are lowercase.
-	// It results there is no matches yaml key and fields of the type.
-	// To avoid it, we unmarshal from JSON.
-	js, err := yaml.YAMLToJSON(b)
+// yamlDocument is a non-empty document of YAML file.
+type yamlDocument struct {
+	// Index is the index of the document in the file, including empty ones.
+	Index int
+	Body  ast.Node
+}
+
+func parseYAMLDocuments(b []byte) ([]yamlDocument, error) {
+	f, err := parser.ParseBytes(b, 0)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	var ret []yamlDocument
+	for i, doc := range f.Docs {
+		if doc.Body == nil {
+			continue
+		}
+		ret = append(ret, yamlDocument{Index: i, Body: doc.Body})
+	}
+	return ret, nil
+}
+
+// documentToJSON converts a document of YAML to JSON.
+// yaml.Unmarshal which compliant with encoding/yaml with types without yaml tag such as CreateScheduleInput assumes all keys are lowercase.
+// It results there is no matches yaml key and fields of the type.
+// To avoid it, we unmarshal from JSON.
+func documentToJSON(n ast.Node) ([]byte, error) {
+	var v any
+	if err := yaml.NodeToValue(n, &v, yaml.UseOrderedMap()); err != nil {
+		return nil, err
+	}
+	return yaml.MarshalWithOptions(v, yaml.JSON())
+}
+
+type inputSchedule struct {
+	FileName string
+	Input    *scheduler.CreateScheduleInput
+	// IgnorePaths are JSON pointers of the fields which are excluded from diff and kept remote values by update.
+	IgnorePaths []string
+}
+
+func (s *inputSchedule) ID() string {
+	return scheduleID(s.Input.GroupName, s.Input.Name)
+}
+
+func scheduleID(groupName, name *string) string {
+	return aws.ToString(groupName) + "/" + aws.ToString(name)
+}
+
+// prepareInputSchedules reads all schedules specified by patterns.
+// Each pattern is a path to schedule.yaml, a directory which is searched recursively for *.yml and *.yaml, or a glob.
+// Overlay files such as *.overlay.yml are excluded from directories and globs.
+func prepareInputSchedules(patterns []string, opts loadOptions) ([]*inputSchedule, error) {
+	files, err := resolveScheduleFiles(patterns, opts.ExcludeDirs)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*inputSchedule
+	seen := map[string]string{}
+	for _, fn := range files {
+		schs, err := prepareInputSchedule(fn, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fn, err)
+		}
+		for _, s := range schs {
+			if prev, ok := seen[s.ID()]; ok {
+				return nil, fmt.Errorf("%s: schedule %s is already defined in %s", fn, s.ID(), prev)
+			}
+			seen[s.ID()] = fn
+			ret = append(ret, s)
+		}
+	}
+
+	return ret, nil
+}
+
+func resolveScheduleFiles(patterns []string, excludeDirs []string) ([]string, error) {
+	excluded := map[string]bool{}
+	for _, d := range excludeDirs {
+		abs, err := filepath.Abs(d)
+		if err != nil {
+			return nil, fmt.Errorf("filepath.Abs(%s): %w", d, err)
+		}
+		excluded[abs] = true
+	}
+
+	var files []string
+	seen := map[string]bool{}
+	add := func(fn string) {
+		if !seen[fn] {
+			seen[fn] = true
+			files = append(files, fn)
+		}
 	}
 
+	for _, p := range patterns {
+		var matches []string
+		if strings.ContainsAny(p, "*?[") {
+			m, err := filepath.Glob(p)
+			if err != nil {
+				return nil, fmt.Errorf("filepath.Glob(%s): %w", p, err)
+			}
+			if len(m) == 0 {
+				return nil, fmt.Errorf("no files match %s", p)
+			}
+			matches = lo.Reject(m, func(fn string, _ int) bool { return isOverlayFile(fn) })
+		} else {
+			matches = []string{p}
+		}
+
+		for _, fn := range matches {
+			st, err := os.Stat(fn)
+			if err != nil || !st.IsDir() {
+				// Let prepareInputSchedule report errors of the file.
+				add(fn)
+				continue
+			}
+
+			err = filepath.WalkDir(fn, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if d.IsDir() {
+					if path != fn && strings.HasPrefix(d.Name(), ".") {
+						return filepath.SkipDir
+					}
+					if abs, err := filepath.Abs(path); err == nil && excluded[abs] {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				if isScheduleFile(path) {
+					add(path)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("filepath.WalkDir(%s): %w", fn, err)
+			}
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no schedule files found in %s", strings.Join(patterns, ", "))
+	}
+	return files, nil
+}
+
+func isScheduleFile(fn string) bool {
+	switch filepath.Ext(fn) {
+	case ".yml", ".yaml":
+		return !isOverlayFile(fn)
+	}
+	return false
+}
+
+// unmarshalScheduleJSON unmarshals the JSON converted from schedule.yaml, which accepts Target.Input as mapping or sequence.
+func unmarshalScheduleJSON(js []byte, out *scheduler.CreateScheduleInput) error {
+	js, err := expandStructuredInput(js)
+	if err != nil {
+		return err
+	}
 	return json.Unmarshal(js, out)
 }
 
+func addScheduleFlag(cmd *cobra.Command) {
+	addOptionalScheduleFlag(cmd)
+	lo.Must0(cmd.MarkFlagRequired(OptSchedule))
+}
+
+func addConcurrencyFlag(cmd *cobra.Command) {
+	cmd.Flags().Int(OptConcurrency, 1, "number of schedules processed in parallel")
+}
+
+func addHistoryDirFlag(cmd *cobra.Command) {
+	cmd.Flags().String(OptHistoryDir, defaultHistoryDir, "directory to save remote schedules before changing them, as <dir>/<group>/<name>/<timestamp>.yml. empty to disable")
+}
+
+func addIgnorePathFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArray(OptIgnorePath, nil, "JSON pointer such as /State of the field to ignore in diff and to keep remote value in update. It can be specified multiple times")
+}
+
+func addOptionalScheduleFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArray(OptSchedule, nil, "path/to/schedule.yaml, directory or glob. It can be specified multiple times")
+	cmd.Flags().Bool(OptStrict, true, "reject unknown, duplicated and missing required fields in schedule.yaml")
+	cmd.Flags().StringArray(OptOverlay, nil, "path/to/overlay.yaml applied to every schedule. It can be specified multiple times")
+	cmd.Flags().String(OptEnv, "", "apply path/to/schedule.<env>.overlay.yml to path/to/schedule.yml if it exists")
+}
+
 func wrapCobra(cmd *cobra.Command, f func(*cobra.Command)) *cobra.Command {
 	f(cmd)
 	return cmd
@@ -0,0 +1,111 @@
+package ebschedule
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/spf13/cobra"
+)
+
+func newDeleteCommand(in *CommandInput) *cobra.Command {
+	return wrapCobra(&cobra.Command{
+		Use:   "delete",
+		Short: "Delete schedule or schedule group",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			patterns, _ := cmd.Flags().GetStringArray(OptSchedule)
+			optName, _ := cmd.Flags().GetString(OptName)
+			optGroup, _ := cmd.Flags().GetString(OptGroup)
+			optDeleteScheduleGroup, _ := cmd.Flags().GetBool(OptDeleteScheduleGroup)
+			optYes, _ := cmd.Flags().GetBool(OptYes)
+
+			if optDeleteScheduleGroup {
+				if len(patterns) > 0 || optName != "" {
+					return fmt.Errorf("--%s cannot be used with --%s or --%s", OptDeleteScheduleGroup, OptSchedule, OptName)
+				}
+				return deleteScheduleGroup(ctx, in.SchedulerClient, optGroup, !optYes, cmd.InOrStdin(), cmd.ErrOrStderr())
+			}
+
+			var targets []*inputSchedule
+			switch {
+			case len(patterns) > 0 && optName != "":
+				return fmt.Errorf("--%s and --%s cannot be used together", OptSchedule, OptName)
+			case len(patterns) > 0:
+				schs, err := prepareInputSchedules(patterns, loadOptionsFromFlags(cmd))
+				if err != nil {
+					return fmt.Errorf("prepareInputSchedules: %w", err)
+				}
+				targets = schs
+			case optName != "":
+				targets = []*inputSchedule{{
+					Input: &scheduler.CreateScheduleInput{
+						Name:      aws.String(optName),
+						GroupName: aws.String(optGroup),
+					},
+				}}
+			default:
+				return fmt.Errorf("either --%s, --%s or --%s must be specified", OptSchedule, OptName, OptDeleteScheduleGroup)
+			}
+
+			var errs []error
+			for _, sch := range targets {
+				_, err := in.SchedulerClient.DeleteSchedule(ctx, &scheduler.DeleteScheduleInput{
+					Name:      sch.Input.Name,
+					GroupName: sch.Input.GroupName,
+				})
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: scheduler.DeleteSchedule: %w", sch.ID(), err))
+					continue
+				}
+				log.Printf("Schedule %s deleted", sch.ID())
+			}
+			return errors.Join(errs...)
+		},
+	}, func(cmd *cobra.Command) {
+		addOptionalScheduleFlag(cmd)
+		cmd.Flags().String(OptName, "", "name of the schedule to delete")
+		cmd.Flags().String(OptGroup, "default", "name of the schedule group")
+		cmd.Flags().Bool(OptDeleteScheduleGroup, false, "delete the schedule group specified by --group instead of schedules")
+		cmd.Flags().Bool(OptYes, false, "do not ask for confirmation")
+	})
+}
+
+// deleteScheduleGroup deletes the schedule group only when it has no schedules.
+func deleteScheduleGroup(ctx context.Context, client SchedulerClient, name string, confirm bool, r io.Reader, w io.Writer) error {
+	schs, err := listSchedules(ctx, client, &scheduler.ListSchedulesInput{
+		GroupName: aws.String(name),
+	})
+	if err != nil {
+		return err
+	}
+	if len(schs) > 0 {
+		return fmt.Errorf("schedule group %s still has %d schedule(s)", name, len(schs))
+	}
+
+	if confirm {
+		fmt.Fprintf(w, "Type the name of the schedule group to delete it (%s): ", name)
+		line, err := bufio.NewReader(r).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("read confirmation: %w", err)
+		}
+		if strings.TrimSpace(line) != name {
+			return fmt.Errorf("deletion of schedule group %s is canceled", name)
+		}
+	}
+
+	_, err = client.DeleteScheduleGroup(ctx, &scheduler.DeleteScheduleGroupInput{
+		Name: aws.String(name),
+	})
+	if err != nil {
+		return fmt.Errorf("scheduler.DeleteScheduleGroup: %w", err)
+	}
+	log.Printf("ScheduleGroup %s deleted", name)
+	return nil
+}
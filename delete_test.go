@@ -0,0 +1,170 @@
+package ebschedule
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/assert"
+	mock_ebschedule "github.com/tckz/ebschedule/mock"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_delete(t *testing.T) {
+	optsIgnoreUnexported := cmpopts.IgnoreUnexported(
+		scheduler.DeleteScheduleInput{},
+		scheduler.DeleteScheduleGroupInput{},
+		scheduler.ListSchedulesInput{},
+	)
+
+	t.Run("by-name", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().DeleteSchedule(gomock.Any(), CmpDiff(&scheduler.DeleteScheduleInput{
+			Name:      aws.String("some-schedule"),
+			GroupName: aws.String("some-group"),
+		}, optsIgnoreUnexported)).
+			Return(&scheduler.DeleteScheduleOutput{}, nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"delete", "--name", "some-schedule", "--group", "some-group"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+		assert.Equal(``, out.String())
+	})
+
+	t.Run("by-schedule", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().DeleteSchedule(gomock.Any(), CmpDiff(&scheduler.DeleteScheduleInput{
+			Name:      aws.String("some-schedule"),
+			GroupName: aws.String("default"),
+		}, optsIgnoreUnexported)).
+			Return(nil, &types.ResourceNotFoundException{})
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"delete", "--schedule", "testdata/update/omit-group.yml"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.EqualError(err, `default/some-schedule: scheduler.DeleteSchedule: ResourceNotFoundException: `)
+	})
+
+	t.Run("group", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().ListSchedules(gomock.Any(), CmpDiff(&scheduler.ListSchedulesInput{
+			GroupName: aws.String("some-group"),
+		}, optsIgnoreUnexported), gomock.Any()).
+			Return(&scheduler.ListSchedulesOutput{}, nil)
+		cl.EXPECT().DeleteScheduleGroup(gomock.Any(), CmpDiff(&scheduler.DeleteScheduleGroupInput{
+			Name: aws.String("some-group"),
+		}, optsIgnoreUnexported)).
+			Return(&scheduler.DeleteScheduleGroupOutput{}, nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		cmd.SetIn(strings.NewReader("some-group\n"))
+		cmd.SetErr(bytes.NewBuffer(nil))
+		ctx := context.Background()
+		cmd.SetArgs([]string{"delete", "--group", "some-group", "--delete-schedule-group"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+	})
+
+	t.Run("group-canceled", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().ListSchedules(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&scheduler.ListSchedulesOutput{}, nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		cmd.SetIn(strings.NewReader("other\n"))
+		cmd.SetErr(bytes.NewBuffer(nil))
+		ctx := context.Background()
+		cmd.SetArgs([]string{"delete", "--group", "some-group", "--delete-schedule-group"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.EqualError(err, `deletion of schedule group some-group is canceled`)
+	})
+
+	t.Run("group-not-empty", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().ListSchedules(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&scheduler.ListSchedulesOutput{
+				Schedules: []types.ScheduleSummary{
+					{GroupName: aws.String("some-group"), Name: aws.String("some-schedule")},
+				},
+			}, nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"delete", "--group", "some-group", "--delete-schedule-group", "--yes"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.EqualError(err, `schedule group some-group still has 1 schedule(s)`)
+	})
+}
@@ -2,10 +2,13 @@ package ebschedule
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/scheduler"
 	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
@@ -18,81 +21,225 @@ import (
 	"github.com/spf13/cobra"
 )
 
+type diffSummary struct {
+	Changed   int
+	Unchanged int
+	Failed    int
+}
+
+func (s *diffSummary) String() string {
+	return fmt.Sprintf("%d schedule(s) compared: %d changed, %d unchanged, %d failed",
+		s.Changed+s.Unchanged+s.Failed, s.Changed, s.Unchanged, s.Failed)
+}
+
+// diffJSONOutput is an element of the output of diff --output json.
+type diffJSONOutput struct {
+	GroupName string
+	Name      string
+	File      string
+	// LastModificationDate of the remote schedule, which can be passed to update --expect-unchanged-since.
+	LastModificationDate *time.Time `json:",omitempty"`
+	// Patch is RFC 6902 JSON Patch which converts the remote schedule to the local one.
+	Patch []jsonPatchOperation
+}
+
 func newDiffCommand(in *CommandInput) *cobra.Command {
 	return wrapCobra(&cobra.Command{
 		Use:   "diff",
 		Short: "Diff schedule configuration",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
-			fn := cmd.Flag(OptSchedule).Value.String()
+			patterns, _ := cmd.Flags().GetStringArray(OptSchedule)
+			optExitCode, _ := cmd.Flags().GetBool(OptExitCode)
+			optOutput, _ := cmd.Flags().GetString(OptOutput)
+			optConcurrency, _ := cmd.Flags().GetInt(OptConcurrency)
 
-			sch, err := prepareInputSchedule(fn)
-			if err != nil {
-				return fmt.Errorf("prepareInputSchedule: %w", err)
+			switch optOutput {
+			case outputFormatText, outputFormatJSON:
+			default:
+				return fmt.Errorf("unknown output format: %s", optOutput)
 			}
 
-			fromYAML := ""
-			fromName := "/dev/null"
+			schs, err := prepareInputSchedules(patterns, loadOptionsFromFlags(cmd))
+			if err != nil {
+				return fmt.Errorf("prepareInputSchedules: %w", err)
+			}
 
-			curSch, err := in.SchedulerClient.GetSchedule(ctx, &scheduler.GetScheduleInput{
-				Name:      sch.Name,
-				GroupName: sch.GroupName,
+			results := runParallel(ctx, optConcurrency, schs, func(ctx context.Context, sch *inputSchedule) (*scheduleDiff, error) {
+				return diffSchedule(ctx, in.SchedulerClient, sch)
 			})
-			if err != nil {
-				var notFound *types.ResourceNotFoundException
-				if !errors.As(err, &notFound) {
-					return fmt.Errorf("scheduler.GetSchedule: %w", err)
-				}
-			} else {
-				fromYAML, err = marshalYAMLForDiff(&curSch)
+
+			var summary diffSummary
+			var errs []error
+			jsonOut := []diffJSONOutput{}
+			for i, sch := range schs {
+				diff, err := results[i].Value, results[i].Err
 				if err != nil {
-					return fmt.Errorf("marshalYAMLForDiff.currentSchedule: %w", err)
+					summary.Failed++
+					errs = append(errs, fmt.Errorf("%s: %w", sch.ID(), err))
+					continue
+				}
+
+				patch := diff.JSONPatch()
+				if len(patch) == 0 {
+					summary.Unchanged++
+				} else {
+					summary.Changed++
+				}
+
+				switch optOutput {
+				case outputFormatJSON:
+					o := diffJSONOutput{
+						GroupName: *sch.Input.GroupName,
+						Name:      *sch.Input.Name,
+						File:      sch.FileName,
+						Patch:     patch,
+					}
+					if diff.Remote != nil {
+						o.LastModificationDate = diff.Remote.LastModificationDate
+					}
+					jsonOut = append(jsonOut, o)
+				default:
+					unified, err := diff.Unified()
+					if err != nil {
+						errs = append(errs, fmt.Errorf("%s: %w", sch.ID(), err))
+						continue
+					}
+					if unified != "" {
+						fmt.Fprint(in.OutWriter, coloredDiff(unified))
+					}
 				}
-				fromName = *curSch.Arn
 			}
+			log.Print(summary.String())
 
-			toYAML, err := marshalYAMLForDiff(&sch)
-			if err != nil {
-				return fmt.Errorf("marshalYAMLForDiff.specifiedSchedule: %w", err)
+			if optOutput == outputFormatJSON {
+				enc := json.NewEncoder(in.OutWriter)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(jsonOut); err != nil {
+					return err
+				}
 			}
 
-			if diff := fmt.Sprint(gotextdiff.ToUnified(fromName, fn, fromYAML,
-				myers.ComputeEdits(span.URIFromPath(fromName), fromYAML, toYAML))); diff != "" {
-				fmt.Fprint(in.OutWriter, coloredDiff(diff))
+			if len(errs) > 0 {
+				return errors.Join(errs...)
+			}
+			if optExitCode && summary.Changed > 0 {
+				return &ExitCodeError{Code: ExitCodeDiffFound, Err: errors.New("differences found")}
 			}
 			return nil
 		},
 	}, func(cmd *cobra.Command) {
-		cmd.Flags().String(OptSchedule, "", "path/to/schedule.yaml")
-		lo.Must0(cmd.MarkFlagRequired(OptSchedule))
+		addScheduleFlag(cmd)
+		addConcurrencyFlag(cmd)
+		addIgnorePathFlag(cmd)
+		cmd.Flags().Bool(OptExitCode, false, fmt.Sprintf("exit with %d if there are differences, 1 on errors and 0 otherwise", ExitCodeDiffFound))
+		cmd.Flags().StringP(OptOutput, "o", outputFormatText, "output format: text or json(RFC 6902 JSON Patch)")
 	})
 }
 
-func normalizeJSON(js []byte) ([]byte, error) {
-	var v any
-	dec := json.NewDecoder(bytes.NewReader(js))
-	dec.UseNumber()
-	err := dec.Decode(&v)
+type scheduleDiff struct {
+	// Remote is the remote schedule. nil if it does not exist.
+	Remote   *scheduler.GetScheduleOutput
+	FromName string
+	ToName   string
+	// From is the normalized remote schedule. nil if it does not exist.
+	From any
+	// To is the normalized local schedule.
+	To any
+}
+
+// Unified returns unified diff. It returns empty string when there is no difference.
+func (d *scheduleDiff) Unified() (string, error) {
+	fromYAML := ""
+	if d.From != nil {
+		y, err := marshalScheduleYAML(d.From)
+		if err != nil {
+			return "", fmt.Errorf("marshalScheduleYAML.currentSchedule: %w", err)
+		}
+		fromYAML = y
+	}
+
+	toYAML, err := marshalScheduleYAML(d.To)
 	if err != nil {
-		return nil, fmt.Errorf("json.Decode: %w", err)
+		return "", fmt.Errorf("marshalScheduleYAML.specifiedSchedule: %w", err)
 	}
 
-	buf := &bytes.Buffer{}
-	enc := json.NewEncoder(buf)
-	enc.SetIndent("", "  ")
-	err = enc.Encode(v)
+	return fmt.Sprint(gotextdiff.ToUnified(d.FromName, d.ToName, fromYAML,
+		myers.ComputeEdits(span.URIFromPath(d.FromName), fromYAML, toYAML))), nil
+}
+
+// JSONPatch returns RFC 6902 JSON Patch from remote to local.
+func (d *scheduleDiff) JSONPatch() []jsonPatchOperation {
+	return computeJSONPatch(d.From, d.To)
+}
+
+// diffSchedule compares remote schedule and specified one.
+func diffSchedule(ctx context.Context, client SchedulerClient, in *inputSchedule) (*scheduleDiff, error) {
+	sch := in.Input
+	ret := &scheduleDiff{
+		FromName: "/dev/null",
+		ToName:   in.FileName,
+	}
+
+	curSch, err := client.GetSchedule(ctx, &scheduler.GetScheduleInput{
+		Name:      sch.Name,
+		GroupName: sch.GroupName,
+	})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if !errors.As(err, &notFound) {
+			return nil, fmt.Errorf("scheduler.GetSchedule: %w", err)
+		}
+	} else {
+		ret.From, err = documentForDiff(curSch, in.IgnorePaths)
+		if err != nil {
+			return nil, fmt.Errorf("documentForDiff.currentSchedule: %w", err)
+		}
+		ret.FromName = lo.FromPtr(curSch.Arn)
+		if curSch.LastModificationDate != nil {
+			// The header of unified diff may have the modification time after a tab, which is what the diff is based on.
+			ret.FromName += "\t" + curSch.LastModificationDate.UTC().Format(time.RFC3339)
+		}
+		ret.Remote = curSch
+	}
+
+	ret.To, err = documentForDiff(sch, in.IgnorePaths)
 	if err != nil {
-		return nil, fmt.Errorf("json.Encode: %w", err)
+		return nil, fmt.Errorf("documentForDiff.specifiedSchedule: %w", err)
 	}
-	return buf.Bytes(), nil
+
+	return ret, nil
 }
 
 func marshalYAMLForDiff(src any) (string, error) {
+	v, err := documentForDiff(src, nil)
+	if err != nil {
+		return "", err
+	}
+	return marshalScheduleYAML(v)
+}
+
+// documentForDiff returns normalized document of the schedule to compare, without the fields at ignorePaths.
+func documentForDiff(src any, ignorePaths []string) (any, error) {
+	v, err := scheduleDocument(src)
+	if err != nil {
+		return nil, err
+	}
+	v, err = normalizeScheduleDocument(v)
+	if err != nil {
+		return nil, fmt.Errorf("normalizeScheduleDocument: %w", err)
+	}
+	return removeIgnoredPaths(v, ignorePaths)
+}
+
+// scheduleDocument converts a schedule such as CreateScheduleInput or GetScheduleOutput to generic document
+// without read-only fields, which is suitable to compare and to write as schedule.yaml.
+func scheduleDocument(src any) (any, error) {
 	// yaml.Marshal which compliant with encoding/yaml with types without yaml tag such as GetScheduleOutput outputs keys as lowercase.
 	// To avoid it, we marshal it to JSON and decode it again.
 	js, err := json.Marshal(src)
 	if err != nil {
-		return "", fmt.Errorf("json.Marshal: %w", err)
+		return nil, fmt.Errorf("json.Marshal: %w", err)
 	}
 
 	dec := json.NewDecoder(bytes.NewReader(js))
@@ -100,8 +247,9 @@ func marshalYAMLForDiff(src any) (string, error) {
 	var v any
 	err = dec.Decode(&v)
 	if err != nil {
-		return "", fmt.Errorf("json.Decode: %w", err)
+		return nil, fmt.Errorf("json.Decode: %w", err)
 	}
+	v = convertJSONNumbers(v)
 
 	for _, p := range []string{
 		"/Arn",
@@ -112,28 +260,59 @@ func marshalYAMLForDiff(src any) (string, error) {
 	} {
 		v, _, err = removeValue(v, p)
 		if err != nil {
-			return "", fmt.Errorf("removeValue(%s): %w", p, err)
+			return nil, fmt.Errorf("removeValue(%s): %w", p, err)
 		}
 	}
 
-	// Try to normalize Target.Input for diff
+	// Target.Input of JSON object or array is compared structurally, and is written as native YAML.
 	var input *string
-	const targetInput = "/Target/Input"
-	found, err := getValue(v, targetInput, &input)
+	found, err := getValue(v, targetInputPath, &input)
 	if err != nil {
-		return "", fmt.Errorf("getValue(%s): %w", targetInput, err)
+		return nil, fmt.Errorf("getValue(%s): %w", targetInputPath, err)
 	}
 	if found && input != nil {
-		js, err := normalizeJSON([]byte(*input))
-		if err == nil {
-			err := setValue(v, targetInput, string(js))
+		if structured, ok := structuredInput(*input); ok {
+			err := setValue(v, targetInputPath, structured)
 			if err != nil {
-				return "", fmt.Errorf("setValue(%s): %w", targetInput, err)
+				return nil, fmt.Errorf("setValue(%s): %w", targetInputPath, err)
 			}
 		}
 	}
 
-	out, err := yaml.MarshalWithOptions(v, yaml.UseLiteralStyleIfMultiline(true))
+	return v, nil
+}
+
+// convertJSONNumbers converts json.Number in v into int64 or float64,
+// so that the fields of the schedule are compared with the typed defaults in normalizeScheduleDocument.
+// Target.Input is not the case, which keeps json.Number. See structuredInput.
+func convertJSONNumbers(v any) any {
+	switch vv := v.(type) {
+	case map[string]any:
+		for k, e := range vv {
+			vv[k] = convertJSONNumbers(e)
+		}
+	case []any:
+		for i, e := range vv {
+			vv[i] = convertJSONNumbers(e)
+		}
+	case json.Number:
+		if n, err := vv.Int64(); err == nil {
+			return n
+		}
+		if f, err := vv.Float64(); err == nil {
+			return f
+		}
+	}
+	return v
+}
+
+func marshalScheduleYAML(v any) (string, error) {
+	out, err := yaml.MarshalWithOptions(v,
+		yaml.UseLiteralStyleIfMultiline(true),
+		// json.Number in Target.Input is written as numeric literal instead of quoted string.
+		yaml.CustomMarshaler[json.Number](func(n json.Number) ([]byte, error) {
+			return []byte(n), nil
+		}))
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,291 @@
+package ebschedule
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/stretchr/testify/assert"
+	mock_ebschedule "github.com/tckz/ebschedule/mock"
+	"go.uber.org/mock/gomock"
+)
+
+// remoteDailyForTest returns remote schedule equivalent to testdata/multi/daily.yaml
+func remoteDailyForTest() *scheduler.GetScheduleOutput {
+	return &scheduler.GetScheduleOutput{
+		Arn:       aws.String("arn:aws:scheduler:ap-northeast-1:99999:schedule/multi-group/daily"),
+		Name:      aws.String("daily"),
+		GroupName: aws.String("multi-group"),
+		FlexibleTimeWindow: &types.FlexibleTimeWindow{
+			Mode: types.FlexibleTimeWindowModeOff,
+		},
+		ScheduleExpression: aws.String("cron(0 3 * * ? *)"),
+		State:              types.ScheduleStateEnabled,
+		Target: &types.Target{
+			Arn:     aws.String("arn:aws:lambda:ap-northeast-1:99999:function:some-func"),
+			RoleArn: aws.String("arn:aws:iam::99999:role/some-scheduler-role"),
+		},
+	}
+}
+
+func Test_diff(t *testing.T) {
+	t.Run("structured-input", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		remote := remoteDailyForTest()
+		remote.Target.Input = aws.String(`{
+  "targets": [{"name": "tmp", "days": 3}],
+  "action": "cleanup"
+}`)
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("daily"),
+			GroupName: aws.String("multi-group"),
+		}).Return(remote, nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"diff", "--schedule", "testdata/update/structured-input.yml", "-o", "json"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+		assert.Equal(`[
+  {
+    "GroupName": "multi-group",
+    "Name": "daily",
+    "File": "testdata/update/structured-input.yml",
+    "Patch": [
+      {
+        "op": "replace",
+        "path": "/Target/Input/targets/0/days",
+        "value": 7
+      }
+    ]
+  }
+]
+`, out.String())
+	})
+
+	t.Run("large-integer", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		// Differs only beyond the precision of float64.
+		remote := remoteDailyForTest()
+		remote.Target.Input = aws.String(`{"orderId":12345678901234567890}`)
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(remote, nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		cmd.SetArgs([]string{"diff", "--schedule", "testdata/update/large-integer.yml", "--exit-code"})
+		err := cmd.ExecuteContext(context.Background())
+
+		var exitCodeErr *ExitCodeError
+		if assert.True(errors.As(err, &exitCodeErr)) {
+			assert.Equal(ExitCodeDiffFound, exitCodeErr.Code)
+		}
+		assert.Contains(out.String(), "-    orderId: 12345678901234567890\n+    orderId: 12345678901234567891\n")
+	})
+
+	t.Run("no-diff", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("daily"),
+			GroupName: aws.String("multi-group"),
+		}).Return(remoteDailyForTest(), nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"diff", "--schedule", "testdata/multi/daily.yaml", "--exit-code"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+		assert.Equal(``, out.String())
+	})
+
+	t.Run("exit-code", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		remote := remoteDailyForTest()
+		remote.State = types.ScheduleStateDisabled
+		remote.LastModificationDate = aws.Time(time.Date(2023, 2, 3, 13, 5, 6, 0, time.FixedZone("JST", 9*60*60)))
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(remote, nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"diff", "--schedule", "testdata/multi/daily.yaml", "--exit-code"})
+		err := cmd.ExecuteContext(ctx)
+
+		var exitCodeErr *ExitCodeError
+		if assert.True(errors.As(err, &exitCodeErr)) {
+			assert.Equal(ExitCodeDiffFound, exitCodeErr.Code)
+		}
+		assert.Equal(`--- arn:aws:scheduler:ap-northeast-1:99999:schedule/multi-group/daily	2023-02-03T04:05:06Z
++++ testdata/multi/daily.yaml
+@@ -5,7 +5,7 @@
+ Name: daily
+ ScheduleExpression: cron(0 3 * * ? *)
+ ScheduleExpressionTimezone: UTC
+-State: DISABLED
++State: ENABLED
+ Target:
+   Arn: arn:aws:lambda:ap-northeast-1:99999:function:some-func
+   RetryPolicy:
+
+`, out.String())
+	})
+
+	t.Run("exit-code-on-error", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(nil, errors.New("err@GetSchedule"))
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"diff", "--schedule", "testdata/multi/daily.yaml", "--exit-code"})
+		err := cmd.ExecuteContext(ctx)
+
+		var exitCodeErr *ExitCodeError
+		assert.False(errors.As(err, &exitCodeErr))
+		assert.EqualError(err, `multi-group/daily: scheduler.GetSchedule: err@GetSchedule`)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		remote := remoteDailyForTest()
+		remote.State = types.ScheduleStateDisabled
+		remote.Target.RoleArn = aws.String("arn:aws:iam::99999:role/other-role")
+		remote.KmsKeyArn = nil
+		remote.LastModificationDate = aws.Time(time.Date(2024, 1, 2, 3, 4, 5, 678000000, time.UTC))
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(remote, nil)
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(nil, &types.ResourceNotFoundException{})
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"diff", "--schedule", "testdata/multi", "-o", "json"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+		assert.Equal(`[
+  {
+    "GroupName": "multi-group",
+    "Name": "daily",
+    "File": "testdata/multi/daily.yaml",
+    "LastModificationDate": "2024-01-02T03:04:05.678Z",
+    "Patch": [
+      {
+        "op": "replace",
+        "path": "/State",
+        "value": "ENABLED"
+      },
+      {
+        "op": "replace",
+        "path": "/Target/RoleArn",
+        "value": "arn:aws:iam::99999:role/some-scheduler-role"
+      }
+    ]
+  },
+  {
+    "GroupName": "multi-group",
+    "Name": "hourly",
+    "File": "testdata/multi/hourly.yml",
+    "Patch": [
+      {
+        "op": "add",
+        "path": "",
+        "value": {
+          "ActionAfterCompletion": "NONE",
+          "FlexibleTimeWindow": {
+            "Mode": "OFF"
+          },
+          "GroupName": "multi-group",
+          "Name": "hourly",
+          "ScheduleExpression": "cron(0 * * * ? *)",
+          "ScheduleExpressionTimezone": "UTC",
+          "State": "ENABLED",
+          "Target": {
+            "Arn": "arn:aws:lambda:ap-northeast-1:99999:function:some-func",
+            "RetryPolicy": {
+              "MaximumEventAgeInSeconds": 86400,
+              "MaximumRetryAttempts": 185
+            },
+            "RoleArn": "arn:aws:iam::99999:role/some-scheduler-role"
+          }
+        }
+      }
+    ]
+  }
+]
+`, out.String())
+	})
+}
@@ -0,0 +1,165 @@
+package ebschedule
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+func newExportCommand(in *CommandInput) *cobra.Command {
+	return wrapCobra(&cobra.Command{
+		Use:   "export",
+		Short: "Export remote schedule as schedule.yaml",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			optName, _ := cmd.Flags().GetString(OptName)
+			optGroup, _ := cmd.Flags().GetString(OptGroup)
+			optOut, _ := cmd.Flags().GetString(OptOut)
+			optDir, _ := cmd.Flags().GetString(OptDir)
+			optAllGroups, _ := cmd.Flags().GetBool(OptAllGroups)
+
+			if optName == "" {
+				if optDir == "" {
+					return fmt.Errorf("--%s is required when --%s is omitted", OptDir, OptName)
+				}
+				groups := []string{optGroup}
+				if optAllGroups {
+					sum, err := listScheduleGroups(ctx, in.SchedulerClient, &scheduler.ListScheduleGroupsInput{})
+					if err != nil {
+						return err
+					}
+					groups = lo.Map(sum, func(e types.ScheduleGroupSummary, _ int) string { return *e.Name })
+				}
+				return exportScheduleGroups(ctx, in.SchedulerClient, groups, optDir)
+			}
+
+			if optAllGroups {
+				return fmt.Errorf("--%s cannot be used with --%s", OptAllGroups, OptName)
+			}
+
+			y, err := exportSchedule(ctx, in.SchedulerClient, optGroup, optName)
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case optOut != "":
+				return writeExportedSchedule(optOut, y)
+			case optDir != "":
+				return writeExportedSchedule(exportedSchedulePath(optDir, optGroup, optName), y)
+			}
+			fmt.Fprint(in.OutWriter, y)
+			return nil
+		},
+	}, func(cmd *cobra.Command) {
+		cmd.Flags().String(OptName, "", "name of the schedule to export. all schedules in the group are exported into --dir if omitted")
+		cmd.Flags().String(OptGroup, "default", "name of the schedule group")
+		cmd.Flags().Bool(OptAllGroups, false, "export schedules of all schedule groups into --dir")
+		cmd.Flags().String(OptOut, "", "path/to/schedule.yaml to write. stdout if omitted")
+		cmd.Flags().String(OptDir, "", "directory to write schedules as <dir>/<group>/<name>.yml")
+		cmd.MarkFlagsMutuallyExclusive(OptOut, OptDir)
+	})
+}
+
+// exportScheduleGroups writes all schedules in the groups to <dir>/<group>/<name>.yml.
+func exportScheduleGroups(ctx context.Context, client SchedulerClient, groups []string, dir string) error {
+	for _, g := range groups {
+		sums, err := listSchedules(ctx, client, &scheduler.ListSchedulesInput{
+			GroupName: aws.String(g),
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", g, err)
+		}
+
+		for _, sum := range sums {
+			y, err := exportSchedule(ctx, client, *sum.GroupName, *sum.Name)
+			if err != nil {
+				return fmt.Errorf("%s: %w", scheduleID(sum.GroupName, sum.Name), err)
+			}
+			if err := writeExportedSchedule(exportedSchedulePath(dir, *sum.GroupName, *sum.Name), y); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func exportedSchedulePath(dir, groupName, name string) string {
+	return filepath.Join(dir, groupName, name+".yml")
+}
+
+// exportSchedule returns remote schedule as YAML which can be consumed by update.
+func exportSchedule(ctx context.Context, client SchedulerClient, groupName, name string) (string, error) {
+	out, err := client.GetSchedule(ctx, &scheduler.GetScheduleInput{
+		Name:      aws.String(name),
+		GroupName: aws.String(groupName),
+	})
+	if err != nil {
+		return "", fmt.Errorf("scheduler.GetSchedule: %w", err)
+	}
+
+	v, err := exportScheduleDocument(out)
+	if err != nil {
+		return "", fmt.Errorf("exportScheduleDocument: %w", err)
+	}
+	return marshalScheduleYAML(v)
+}
+
+// exportScheduleDocument converts the remote schedule to the document to be written as schedule.yaml.
+// Nulls, empty values and empty strings are dropped, because they are zero values of the SDK types
+// such as ActionAfterCompletion which the remote schedule does not have, rather than settings.
+// Target.Input is kept as it is, whose empty values are the data sent to the target.
+func exportScheduleDocument(src *scheduler.GetScheduleOutput) (any, error) {
+	v, err := scheduleDocument(src)
+	if err != nil {
+		return nil, err
+	}
+	v, err = dropEmptyExceptInput(v, func(v any) any {
+		return dropEmptyValues(dropEmptyStrings(v))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return map[string]any{}, nil
+	}
+	return v, nil
+}
+
+// dropEmptyStrings removes fields of empty string recursively.
+func dropEmptyStrings(v any) any {
+	switch vv := v.(type) {
+	case map[string]any:
+		for k, e := range vv {
+			if e == "" {
+				delete(vv, k)
+				continue
+			}
+			vv[k] = dropEmptyStrings(e)
+		}
+	case []any:
+		for i, e := range vv {
+			vv[i] = dropEmptyStrings(e)
+		}
+	}
+	return v
+}
+
+func writeExportedSchedule(fn string, y string) error {
+	if err := os.MkdirAll(filepath.Dir(fn), 0755); err != nil {
+		return fmt.Errorf("os.MkdirAll: %w", err)
+	}
+	if err := os.WriteFile(fn, []byte(y), 0644); err != nil {
+		return fmt.Errorf("os.WriteFile: %w", err)
+	}
+	log.Printf("Exported to %s", fn)
+	return nil
+}
@@ -0,0 +1,224 @@
+package ebschedule
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	mock_ebschedule "github.com/tckz/ebschedule/mock"
+	"go.uber.org/mock/gomock"
+)
+
+func remoteScheduleForTest() *scheduler.GetScheduleOutput {
+	return &scheduler.GetScheduleOutput{
+		Arn:                  aws.String("arn:aws:scheduler:ap-northeast-1:99999:schedule/some-group/some-schedule"),
+		CreationDate:         aws.Time(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)),
+		LastModificationDate: aws.Time(time.Date(2023, 2, 3, 4, 5, 6, 0, time.UTC)),
+		Name:                 aws.String("some-schedule"),
+		GroupName:            aws.String("some-group"),
+		FlexibleTimeWindow: &types.FlexibleTimeWindow{
+			Mode: types.FlexibleTimeWindowModeOff,
+		},
+		ScheduleExpression:         aws.String("cron(*/3 * * * ? *)"),
+		ScheduleExpressionTimezone: aws.String("Asia/Tokyo"),
+		State:                      types.ScheduleStateEnabled,
+		Target: &types.Target{
+			Arn:     aws.String("arn:aws:lambda:ap-northeast-1:99999:function:some-func"),
+			RoleArn: aws.String("arn:aws:iam::99999:role/some-scheduler-role"),
+			Input:   aws.String(`{"key":"value","list":[1,2]}`),
+		},
+	}
+}
+
+func Test_export(t *testing.T) {
+	t.Run("stdout", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("some-schedule"),
+			GroupName: aws.String("some-group"),
+		}).Return(remoteScheduleForTest(), nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"export", "--name", "some-schedule", "--group", "some-group"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+		assert.Equal(`FlexibleTimeWindow:
+  Mode: "OFF"
+GroupName: some-group
+Name: some-schedule
+ScheduleExpression: cron(*/3 * * * ? *)
+ScheduleExpressionTimezone: Asia/Tokyo
+State: ENABLED
+Target:
+  Arn: arn:aws:lambda:ap-northeast-1:99999:function:some-func
+  Input:
+    key: value
+    list:
+    - 1
+    - 2
+  RoleArn: arn:aws:iam::99999:role/some-scheduler-role
+`, out.String())
+	})
+
+	t.Run("round-trip", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).
+			Return(remoteScheduleForTest(), nil)
+
+		fn := filepath.Join(t.TempDir(), "some-group", "some-schedule.yml")
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"export", "--name", "some-schedule", "--group", "some-group", "--out", fn})
+		err := cmd.ExecuteContext(ctx)
+		assert.NoError(err)
+		assert.Equal(``, out.String())
+
+		_, err = os.Stat(fn)
+		assert.NoError(err)
+
+		schs, err := prepareInputSchedule(fn, loadOptions{Strict: true})
+		assert.NoError(err)
+		assert.Len(schs, 1)
+
+		expected, err := marshalYAMLForDiff(remoteScheduleForTest())
+		assert.NoError(err)
+		actual, err := marshalYAMLForDiff(schs[0].Input)
+		assert.NoError(err)
+		assert.Equal(expected, actual)
+	})
+
+	t.Run("all-groups", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().ListScheduleGroups(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&scheduler.ListScheduleGroupsOutput{
+				ScheduleGroups: []types.ScheduleGroupSummary{
+					{Name: aws.String("default")},
+					{Name: aws.String("some-group")},
+				},
+			}, nil)
+		cl.EXPECT().ListSchedules(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, in *scheduler.ListSchedulesInput, _ ...func(*scheduler.Options)) (*scheduler.ListSchedulesOutput, error) {
+				if *in.GroupName == "default" {
+					return &scheduler.ListSchedulesOutput{}, nil
+				}
+				return &scheduler.ListSchedulesOutput{
+					Schedules: []types.ScheduleSummary{
+						{GroupName: aws.String("some-group"), Name: aws.String("some-schedule")},
+						{GroupName: aws.String("some-group"), Name: aws.String("other-schedule")},
+					},
+				}, nil
+			}).Times(2)
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, in *scheduler.GetScheduleInput, _ ...func(*scheduler.Options)) (*scheduler.GetScheduleOutput, error) {
+				ret := remoteScheduleForTest()
+				ret.Name = in.Name
+				return ret, nil
+			}).Times(2)
+
+		dir := t.TempDir()
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"export", "--all-groups", "--dir", dir})
+		err := cmd.ExecuteContext(ctx)
+		assert.NoError(err)
+
+		schs, err := prepareInputSchedules([]string{dir}, loadOptions{Strict: true})
+		assert.NoError(err)
+		assert.Equal([]string{
+			filepath.Join(dir, "some-group", "other-schedule.yml"),
+			filepath.Join(dir, "some-group", "some-schedule.yml"),
+		}, lo.Map(schs, func(e *inputSchedule, _ int) string { return e.FileName }))
+	})
+
+	t.Run("err-wo-dir", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: mock_ebschedule.NewMockSchedulerClient(ctrl),
+			OutWriter:       bytes.NewBuffer(nil),
+		})
+		cmd.SetArgs([]string{"export", "--group", "some-group"})
+		err := cmd.ExecuteContext(context.Background())
+		assert.EqualError(err, `--dir is required when --name is omitted`)
+	})
+}
+
+func Test_exportScheduleDocument(t *testing.T) {
+	assert := assert.New(t)
+
+	remote := remoteScheduleForTest()
+	remote.Description = aws.String("")
+	remote.Target.Input = aws.String(`{"empty":"","null":null,"object":{}}`)
+
+	v, err := exportScheduleDocument(remote)
+	assert.NoError(err)
+	y, err := marshalScheduleYAML(v)
+	assert.NoError(err)
+	// Empty values in Target.Input are kept.
+	assert.Equal(`FlexibleTimeWindow:
+  Mode: "OFF"
+GroupName: some-group
+Name: some-schedule
+ScheduleExpression: cron(*/3 * * * ? *)
+ScheduleExpressionTimezone: Asia/Tokyo
+State: ENABLED
+Target:
+  Arn: arn:aws:lambda:ap-northeast-1:99999:function:some-func
+  Input:
+    empty: ""
+    "null": null
+    object: {}
+  RoleArn: arn:aws:iam::99999:role/some-scheduler-role
+`, y)
+}
@@ -0,0 +1,446 @@
+package ebschedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scheduleExpression is parsed ScheduleExpression of EventBridge Scheduler.
+// https://docs.aws.amazon.com/scheduler/latest/UserGuide/schedule-types.html
+type scheduleExpression interface {
+	String() string
+}
+
+type atExpression struct {
+	src string
+	// At is the wall clock time in ScheduleExpressionTimezone.
+	At time.Time
+}
+
+func (e *atExpression) String() string { return e.src }
+
+type rateExpression struct {
+	src   string
+	Every time.Duration
+}
+
+func (e *rateExpression) String() string { return e.src }
+
+type cronExpression struct {
+	src        string
+	Minutes    []bool
+	Hours      []bool
+	DayOfMonth cronDayOfMonth
+	Months     []bool
+	DayOfWeek  cronDayOfWeek
+	Years      []bool
+}
+
+func (e *cronExpression) String() string { return e.src }
+
+type cronDayOfMonth struct {
+	// Any is true for '?'.
+	Any  bool
+	Days []bool
+	// Last is true for 'L', the last day of the month.
+	Last bool
+	// LastWeekday is true for 'LW', the last weekday of the month.
+	LastWeekday bool
+	// NearestWeekday is n of 'nW', the weekday closest to the n-th day of the month. 0 if not specified.
+	NearestWeekday int
+}
+
+type cronDayOfWeek struct {
+	// Any is true for '?'.
+	Any bool
+	// Days is indexed by 1(SUN) to 7(SAT).
+	Days []bool
+	// LastOf is n of 'nL', the last n-th day of the week in the month. 0 if not specified.
+	LastOf int
+	// Nth and NthDay are k and n of 'n#k', the k-th n-th day of the week in the month. 0 if not specified.
+	Nth    int
+	NthDay int
+}
+
+const (
+	cronYearMin = 1970
+	cronYearMax = 2199
+)
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronFieldMinutes = cronField{name: "Minutes", min: 0, max: 59}
+	cronFieldHours   = cronField{name: "Hours", min: 0, max: 23}
+	cronFieldDOM     = cronField{name: "Day-of-month", min: 1, max: 31}
+	cronFieldMonth   = cronField{name: "Month", min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}}
+	cronFieldDOW = cronField{name: "Day-of-week", min: 1, max: 7, names: map[string]int{
+		"SUN": 1, "MON": 2, "TUE": 3, "WED": 4, "THU": 5, "FRI": 6, "SAT": 7,
+	}}
+	cronFieldYear = cronField{name: "Year", min: cronYearMin, max: cronYearMax}
+)
+
+// parseScheduleExpression parses at(), rate() or cron() expression.
+func parseScheduleExpression(s string) (scheduleExpression, error) {
+	body, kind, ok := cutExpression(s)
+	if !ok {
+		return nil, fmt.Errorf("%q must be one of at(...), rate(...) or cron(...)", s)
+	}
+
+	var e scheduleExpression
+	var err error
+	switch kind {
+	case "at":
+		e, err = parseAtExpression(s, body)
+	case "rate":
+		e, err = parseRateExpression(s, body)
+	default:
+		e, err = parseCronExpression(s, body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", s, err)
+	}
+	return e, nil
+}
+
+func cutExpression(s string) (body string, kind string, ok bool) {
+	for _, k := range []string{"at", "rate", "cron"} {
+		if b, found := strings.CutPrefix(s, k+"("); found {
+			if b, found := strings.CutSuffix(b, ")"); found {
+				return b, k, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+func parseAtExpression(src, body string) (*atExpression, error) {
+	t, err := time.Parse("2006-01-02T15:04:05", body)
+	if err != nil {
+		return nil, fmt.Errorf("date must be yyyy-mm-ddThh:mm:ss: %q", body)
+	}
+	return &atExpression{src: src, At: t}, nil
+}
+
+func parseRateExpression(src, body string) (*rateExpression, error) {
+	fields := strings.Fields(body)
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("rate must be 'value unit': %q", body)
+	}
+
+	v, err := strconv.Atoi(fields[0])
+	if err != nil || v <= 0 {
+		return nil, fmt.Errorf("value must be a positive integer: %q", fields[0])
+	}
+
+	var unit time.Duration
+	singular := ""
+	switch fields[1] {
+	case "minute", "minutes":
+		unit, singular = time.Minute, "minute"
+	case "hour", "hours":
+		unit, singular = time.Hour, "hour"
+	case "day", "days":
+		unit, singular = 24*time.Hour, "day"
+	default:
+		return nil, fmt.Errorf("unit must be one of minute(s), hour(s) or day(s): %q", fields[1])
+	}
+	if v == 1 && fields[1] != singular {
+		return nil, fmt.Errorf("unit must be singular when value is 1: %q", fields[1])
+	}
+	if v > 1 && fields[1] == singular {
+		return nil, fmt.Errorf("unit must be plural when value is greater than 1: %q", fields[1])
+	}
+
+	return &rateExpression{src: src, Every: time.Duration(v) * unit}, nil
+}
+
+func parseCronExpression(src, body string) (*cronExpression, error) {
+	fields := strings.Fields(body)
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("cron must have 6 fields(Minutes Hours Day-of-month Month Day-of-week Year), but %d", len(fields))
+	}
+
+	e := &cronExpression{src: src}
+	var err error
+	if e.Minutes, err = parseCronList(cronFieldMinutes, fields[0]); err != nil {
+		return nil, err
+	}
+	if e.Hours, err = parseCronList(cronFieldHours, fields[1]); err != nil {
+		return nil, err
+	}
+	if e.DayOfMonth, err = parseCronDayOfMonth(fields[2]); err != nil {
+		return nil, err
+	}
+	if e.Months, err = parseCronList(cronFieldMonth, fields[3]); err != nil {
+		return nil, err
+	}
+	if e.DayOfWeek, err = parseCronDayOfWeek(fields[4]); err != nil {
+		return nil, err
+	}
+	if e.Years, err = parseCronList(cronFieldYear, fields[5]); err != nil {
+		return nil, err
+	}
+
+	if e.DayOfMonth.Any == e.DayOfWeek.Any {
+		return nil, fmt.Errorf(`one of Day-of-month or Day-of-week must be "?", but Day-of-month=%q, Day-of-week=%q`, fields[2], fields[4])
+	}
+	return e, nil
+}
+
+func parseCronDayOfMonth(s string) (cronDayOfMonth, error) {
+	switch {
+	case s == "?":
+		return cronDayOfMonth{Any: true}, nil
+	case s == "L":
+		return cronDayOfMonth{Last: true}, nil
+	case s == "LW":
+		return cronDayOfMonth{LastWeekday: true}, nil
+	case strings.HasSuffix(s, "W"):
+		n, err := parseCronValue(cronFieldDOM, strings.TrimSuffix(s, "W"))
+		if err != nil {
+			return cronDayOfMonth{}, err
+		}
+		return cronDayOfMonth{NearestWeekday: n}, nil
+	}
+
+	days, err := parseCronList(cronFieldDOM, s)
+	if err != nil {
+		return cronDayOfMonth{}, err
+	}
+	return cronDayOfMonth{Days: days}, nil
+}
+
+func parseCronDayOfWeek(s string) (cronDayOfWeek, error) {
+	switch {
+	case s == "?":
+		return cronDayOfWeek{Any: true}, nil
+	case s == "L":
+		// The last day of the week, i.e. Saturday.
+		days := make([]bool, cronFieldDOW.max+1)
+		days[cronFieldDOW.max] = true
+		return cronDayOfWeek{Days: days}, nil
+	case strings.HasSuffix(s, "L"):
+		n, err := parseCronValue(cronFieldDOW, strings.TrimSuffix(s, "L"))
+		if err != nil {
+			return cronDayOfWeek{}, err
+		}
+		return cronDayOfWeek{LastOf: n}, nil
+	case strings.Contains(s, "#"):
+		d, k, _ := strings.Cut(s, "#")
+		n, err := parseCronValue(cronFieldDOW, d)
+		if err != nil {
+			return cronDayOfWeek{}, err
+		}
+		nth, err := strconv.Atoi(k)
+		if err != nil || nth < 1 || nth > 5 {
+			return cronDayOfWeek{}, fmt.Errorf("%s: nth of '#' must be 1 to 5: %q", cronFieldDOW.name, s)
+		}
+		return cronDayOfWeek{Nth: nth, NthDay: n}, nil
+	}
+
+	days, err := parseCronList(cronFieldDOW, s)
+	if err != nil {
+		return cronDayOfWeek{}, err
+	}
+	return cronDayOfWeek{Days: days}, nil
+}
+
+// parseCronList parses comma separated list of '*', value, range and increments.
+func parseCronList(f cronField, s string) ([]bool, error) {
+	ret := make([]bool, f.max+1)
+	for _, item := range strings.Split(s, ",") {
+		if item == "" {
+			return nil, fmt.Errorf("%s: empty value in %q", f.name, s)
+		}
+
+		rng, stepStr, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			v, err := strconv.Atoi(stepStr)
+			if err != nil || v <= 0 {
+				return nil, fmt.Errorf("%s: increment must be a positive integer: %q", f.name, item)
+			}
+			step = v
+		}
+
+		var from, to int
+		switch {
+		case rng == "*":
+			from, to = f.min, f.max
+		case strings.Contains(rng, "-"):
+			a, b, _ := strings.Cut(rng, "-")
+			var err error
+			if from, err = parseCronValue(f, a); err != nil {
+				return nil, err
+			}
+			if to, err = parseCronValue(f, b); err != nil {
+				return nil, err
+			}
+			if from > to {
+				return nil, fmt.Errorf("%s: range must be ascending: %q", f.name, item)
+			}
+		default:
+			v, err := parseCronValue(f, rng)
+			if err != nil {
+				return nil, err
+			}
+			from, to = v, v
+			if hasStep {
+				// "n/step" means from n to the max.
+				to = f.max
+			}
+		}
+
+		for v := from; v <= to; v += step {
+			ret[v] = true
+		}
+	}
+	return ret, nil
+}
+
+func parseCronValue(f cronField, s string) (int, error) {
+	if v, ok := f.names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid value: %q", f.name, s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s: value must be %d to %d: %q", f.name, f.min, f.max, s)
+	}
+	return v, nil
+}
+
+// next returns the first fire time strictly after `after` in the location of `after`.
+// false is returned if it never fires again.
+func (e *cronExpression) next(after time.Time) (time.Time, bool) {
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	year, month, day := t.Date()
+	first := true
+	for year <= cronYearMax {
+		if !e.Years[year] {
+			year, month, day, first = year+1, time.January, 1, false
+			continue
+		}
+		if !e.Months[month] {
+			if month == time.December {
+				year, month, day, first = year+1, time.January, 1, false
+			} else {
+				month, day, first = month+1, 1, false
+			}
+			continue
+		}
+
+		lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+		for ; day <= lastDay; day++ {
+			if !e.matchDay(year, month, day, lastDay) {
+				first = false
+				continue
+			}
+
+			hour, minute := 0, 0
+			if first {
+				hour, minute = t.Hour(), t.Minute()
+			}
+			for h := hour; h < len(e.Hours); h++ {
+				if !e.Hours[h] {
+					continue
+				}
+				m := 0
+				if h == hour {
+					m = minute
+				}
+				for ; m < len(e.Minutes); m++ {
+					if !e.Minutes[m] {
+						continue
+					}
+					ret := time.Date(year, month, day, h, m, 0, 0, loc)
+					if ret.Hour() != h || ret.Minute() != m {
+						// The time does not exist due to the spring-forward of DST, which Scheduler skips.
+						continue
+					}
+					// The time occurs twice due to the fall-back, which Scheduler runs only once.
+					// time.Date returns the first one, and the second one is never returned because of After.
+					if ret.After(after) {
+						return ret, true
+					}
+				}
+			}
+			first = false
+		}
+
+		if month == time.December {
+			year, month = year+1, time.January
+		} else {
+			month++
+		}
+		day = 1
+	}
+	return time.Time{}, false
+}
+
+func (e *cronExpression) matchDay(year int, month time.Month, day, lastDay int) bool {
+	weekday := func(d int) time.Weekday {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC).Weekday()
+	}
+
+	if !e.DayOfMonth.Any {
+		dom := e.DayOfMonth
+		switch {
+		case dom.Last:
+			return day == lastDay
+		case dom.LastWeekday:
+			d := lastDay
+			switch weekday(d) {
+			case time.Saturday:
+				d--
+			case time.Sunday:
+				d -= 2
+			}
+			return day == d
+		case dom.NearestWeekday > 0:
+			d := dom.NearestWeekday
+			if d > lastDay {
+				return false
+			}
+			switch weekday(d) {
+			case time.Saturday:
+				if d == 1 {
+					d += 2
+				} else {
+					d--
+				}
+			case time.Sunday:
+				if d == lastDay {
+					d -= 2
+				} else {
+					d++
+				}
+			}
+			return day == d
+		}
+		return dom.Days[day]
+	}
+
+	dow := e.DayOfWeek
+	wd := int(weekday(day)) + 1
+	switch {
+	case dow.LastOf > 0:
+		return wd == dow.LastOf && day+7 > lastDay
+	case dow.Nth > 0:
+		return wd == dow.NthDay && (day-1)/7+1 == dow.Nth
+	}
+	return dow.Days[wd]
+}
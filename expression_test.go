@@ -0,0 +1,144 @@
+package ebschedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseScheduleExpression(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		wantErr string
+	}{
+		{name: "at", src: "at(2024-01-02T03:04:05)"},
+		{name: "rate minute", src: "rate(1 minute)"},
+		{name: "rate hours", src: "rate(12 hours)"},
+		{name: "rate days", src: "rate(3 days)"},
+		{name: "cron", src: "cron(*/3 * * * ? *)"},
+		{name: "cron names", src: "cron(0 9 ? JAN-MAR MON-FRI *)"},
+		{name: "cron list and range", src: "cron(0,30 8-17/2 1,15 * ? 2024-2030)"},
+		{name: "cron L", src: "cron(0 0 L * ? *)"},
+		{name: "cron LW", src: "cron(0 0 LW * ? *)"},
+		{name: "cron W", src: "cron(0 0 3W * ? *)"},
+		{name: "cron nL", src: "cron(0 0 ? * 5L *)"},
+		{name: "cron #", src: "cron(0 0 ? * 2#1 *)"},
+		{
+			name:    "unknown",
+			src:     "every(1 minute)",
+			wantErr: `"every(1 minute)" must be one of at(...), rate(...) or cron(...)`,
+		},
+		{
+			name:    "at invalid",
+			src:     "at(2024-01-02 03:04:05)",
+			wantErr: `"at(2024-01-02 03:04:05)": date must be yyyy-mm-ddThh:mm:ss: "2024-01-02 03:04:05"`,
+		},
+		{
+			name:    "rate zero",
+			src:     "rate(0 minutes)",
+			wantErr: `"rate(0 minutes)": value must be a positive integer: "0"`,
+		},
+		{
+			name:    "rate plural",
+			src:     "rate(1 hours)",
+			wantErr: `"rate(1 hours)": unit must be singular when value is 1: "hours"`,
+		},
+		{
+			name:    "rate singular",
+			src:     "rate(5 minute)",
+			wantErr: `"rate(5 minute)": unit must be plural when value is greater than 1: "minute"`,
+		},
+		{
+			name:    "rate unit",
+			src:     "rate(5 weeks)",
+			wantErr: `"rate(5 weeks)": unit must be one of minute(s), hour(s) or day(s): "weeks"`,
+		},
+		{
+			name:    "cron fields",
+			src:     "cron(0 3 * * ?)",
+			wantErr: `"cron(0 3 * * ?)": cron must have 6 fields(Minutes Hours Day-of-month Month Day-of-week Year), but 5`,
+		},
+		{
+			name:    "cron without ?",
+			src:     "cron(0 3 * * * *)",
+			wantErr: `"cron(0 3 * * * *)": one of Day-of-month or Day-of-week must be "?", but Day-of-month="*", Day-of-week="*"`,
+		},
+		{
+			name:    "cron both ?",
+			src:     "cron(0 3 ? * ? *)",
+			wantErr: `"cron(0 3 ? * ? *)": one of Day-of-month or Day-of-week must be "?", but Day-of-month="?", Day-of-week="?"`,
+		},
+		{
+			name:    "cron day-of-week",
+			src:     "cron(0 3 ? * 8 *)",
+			wantErr: `"cron(0 3 ? * 8 *)": Day-of-week: value must be 1 to 7: "8"`,
+		},
+		{
+			name:    "cron minutes",
+			src:     "cron(60 3 * * ? *)",
+			wantErr: `"cron(60 3 * * ? *)": Minutes: value must be 0 to 59: "60"`,
+		},
+		{
+			name:    "cron month name",
+			src:     "cron(0 3 * FOO ? *)",
+			wantErr: `"cron(0 3 * FOO ? *)": Month: invalid value: "FOO"`,
+		},
+		{
+			name:    "cron range",
+			src:     "cron(0 17-8 * * ? *)",
+			wantErr: `"cron(0 17-8 * * ? *)": Hours: range must be ascending: "17-8"`,
+		},
+		{
+			name:    "cron increment",
+			src:     "cron(*/0 * * * ? *)",
+			wantErr: `"cron(*/0 * * * ? *)": Minutes: increment must be a positive integer: "*/0"`,
+		},
+		{
+			name:    "cron year",
+			src:     "cron(0 3 * * ? 2200)",
+			wantErr: `"cron(0 3 * * ? 2200)": Year: value must be 1970 to 2199: "2200"`,
+		},
+		{
+			name:    "cron #",
+			src:     "cron(0 3 ? * 2#6 *)",
+			wantErr: `"cron(0 3 ? * 2#6 *)": Day-of-week: nth of '#' must be 1 to 5: "2#6"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := parseScheduleExpression(tt.src)
+			if tt.wantErr != "" {
+				assert.EqualError(err, tt.wantErr)
+				return
+			}
+			if assert.NoError(err) {
+				assert.Equal(tt.src, got.String())
+			}
+		})
+	}
+
+	t.Run("parsed", func(t *testing.T) {
+		assert := assert.New(t)
+
+		got, err := parseScheduleExpression("rate(2 hours)")
+		assert.NoError(err)
+		assert.Equal(2*time.Hour, got.(*rateExpression).Every)
+
+		got, err = parseScheduleExpression("cron(10-20/5 * ? * 2#3 *)")
+		assert.NoError(err)
+		e := got.(*cronExpression)
+		var minutes []int
+		for i, ok := range e.Minutes {
+			if ok {
+				minutes = append(minutes, i)
+			}
+		}
+		assert.Equal([]int{10, 15, 20}, minutes)
+		assert.True(e.DayOfMonth.Any)
+		assert.Equal(cronDayOfWeek{Nth: 3, NthDay: 2}, e.DayOfWeek)
+	})
+}
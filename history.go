@@ -0,0 +1,157 @@
+package ebschedule
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultHistoryDir = ".ebschedule/history"
+	// historyTimeFormat is the name of snapshot files, which sorts in chronological order.
+	historyTimeFormat = "20060102T150405.000Z"
+	historyExt        = ".yml"
+)
+
+// historyScheduleDir returns the directory which holds the snapshots of the schedule.
+func historyScheduleDir(dir, groupName, name string) string {
+	return filepath.Join(dir, groupName, name)
+}
+
+// saveHistory writes the remote schedule as schedule.yaml to <dir>/<group>/<name>/<timestamp>.yml before it is changed.
+func saveHistory(dir string, groupName, name *string, remote *scheduler.GetScheduleOutput) (string, error) {
+	v, err := exportScheduleDocument(remote)
+	if err != nil {
+		return "", fmt.Errorf("exportScheduleDocument: %w", err)
+	}
+	y, err := marshalScheduleYAML(v)
+	if err != nil {
+		return "", fmt.Errorf("marshalScheduleYAML: %w", err)
+	}
+
+	sd := historyScheduleDir(dir, aws.ToString(groupName), aws.ToString(name))
+	if err := os.MkdirAll(sd, 0755); err != nil {
+		return "", fmt.Errorf("os.MkdirAll: %w", err)
+	}
+	fn := filepath.Join(sd, timeNow().UTC().Format(historyTimeFormat)+historyExt)
+	if err := os.WriteFile(fn, []byte(y), 0644); err != nil {
+		return "", fmt.Errorf("os.WriteFile: %w", err)
+	}
+	return fn, nil
+}
+
+// listHistory returns the snapshot files of the schedule from oldest to latest.
+func listHistory(dir, groupName, name string) ([]string, error) {
+	sd := historyScheduleDir(dir, groupName, name)
+	entries, err := os.ReadDir(sd)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("os.ReadDir: %w", err)
+	}
+
+	var ret []string
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != historyExt {
+			continue
+		}
+		ret = append(ret, filepath.Join(sd, e.Name()))
+	}
+	slices.Sort(ret)
+	return ret, nil
+}
+
+// resolveSnapshot returns the snapshot file to roll back to.
+// snapshot is a path to the file or a timestamp in the history of the schedule. The latest one is used if it is empty.
+func resolveSnapshot(dir, groupName, name, snapshot string) (string, error) {
+	if snapshot == "" {
+		files, err := listHistory(dir, groupName, name)
+		if err != nil {
+			return "", err
+		}
+		if len(files) == 0 {
+			return "", fmt.Errorf("no snapshot of %s/%s in %s", groupName, name, dir)
+		}
+		return files[len(files)-1], nil
+	}
+
+	if _, err := os.Stat(snapshot); err == nil {
+		return snapshot, nil
+	}
+	fn := filepath.Join(historyScheduleDir(dir, groupName, name), strings.TrimSuffix(snapshot, historyExt)+historyExt)
+	if _, err := os.Stat(fn); err != nil {
+		return "", fmt.Errorf("snapshot %s does not exist: %w", snapshot, err)
+	}
+	return fn, nil
+}
+
+func newRollbackCommand(in *CommandInput) *cobra.Command {
+	return wrapCobra(&cobra.Command{
+		Use:   "rollback",
+		Short: "Restore schedule from the history saved by update",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			optName, _ := cmd.Flags().GetString(OptName)
+			optGroup, _ := cmd.Flags().GetString(OptGroup)
+			optSnapshot, _ := cmd.Flags().GetString(OptSnapshot)
+			optHistoryDir, _ := cmd.Flags().GetString(OptHistoryDir)
+			optCreateScheduleGroup, _ := cmd.Flags().GetBool(OptCreateScheduleGroup)
+			optDryRun, _ := cmd.Flags().GetBool(OptDryRun)
+
+			if optHistoryDir == "" {
+				return fmt.Errorf("--%s is required", OptHistoryDir)
+			}
+
+			fn, err := resolveSnapshot(optHistoryDir, optGroup, optName, optSnapshot)
+			if err != nil {
+				return err
+			}
+			schs, err := prepareInputSchedule(fn, loadOptions{Strict: true})
+			if err != nil {
+				return fmt.Errorf("prepareInputSchedule: %w", err)
+			}
+			if len(schs) != 1 {
+				return fmt.Errorf("%s: snapshot must have exactly one schedule", fn)
+			}
+			if id := schs[0].ID(); id != scheduleID(&optGroup, &optName) {
+				return fmt.Errorf("%s: snapshot is of %s, not %s", fn, id, scheduleID(&optGroup, &optName))
+			}
+			log.Printf("Roll back %s to %s", schs[0].ID(), fn)
+
+			u := &updater{
+				client:              in.SchedulerClient,
+				out:                 in.OutWriter,
+				createScheduleGroup: optCreateScheduleGroup,
+				dryRun:              optDryRun,
+				historyDir:          optHistoryDir,
+				checkedGroups:       map[string]error{},
+			}
+			summary, errs := u.updateAll(ctx, schs)
+			if optDryRun {
+				log.Printf("(dry-run) %s", summary.String())
+			} else {
+				log.Print(summary.String())
+			}
+			return errors.Join(errs...)
+		},
+	}, func(cmd *cobra.Command) {
+		cmd.Flags().String(OptName, "", "name of the schedule to roll back")
+		cmd.Flags().String(OptGroup, "default", "name of the schedule group")
+		cmd.Flags().String(OptSnapshot, "", "timestamp such as 20240102T030405.678Z or path/to/snapshot.yml to restore. the latest one if omitted")
+		cmd.Flags().Bool(OptCreateScheduleGroup, true, "create schedule group if not exist")
+		cmd.Flags().Bool(OptDryRun, false, "output API inputs which would be sent instead of creating or updating")
+		cmd.Flags().String(OptHistoryDir, defaultHistoryDir, "directory of the history saved by update")
+		lo.Must0(cmd.MarkFlagRequired(OptName))
+	})
+}
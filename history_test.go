@@ -0,0 +1,330 @@
+package ebschedule
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/stretchr/testify/assert"
+	mock_ebschedule "github.com/tckz/ebschedule/mock"
+	"go.uber.org/mock/gomock"
+)
+
+// saveHistoryForTest saves remote as the snapshot at the time.
+func saveHistoryForTest(t *testing.T, dir string, at time.Time, remote *scheduler.GetScheduleOutput) string {
+	t.Helper()
+	defer func(f func() time.Time) { timeNow = f }(timeNow)
+	timeNow = func() time.Time { return at }
+
+	fn, err := saveHistory(dir, remote.GroupName, remote.Name, remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fn
+}
+
+func Test_history(t *testing.T) {
+	t.Run("update", func(t *testing.T) {
+		assert := assert.New(t)
+
+		defer func(f func() time.Time) { timeNow = f }(timeNow)
+		timeNow = func() time.Time { return time.Date(2024, 1, 2, 12, 4, 5, 678000000, time.FixedZone("JST", 9*60*60)) }
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		remote := remoteNormalForTest()
+		remote.LastModificationDate = aws.Time(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC))
+		cl.EXPECT().GetScheduleGroup(gomock.Any(), gomock.Any()).
+			Return(&scheduler.GetScheduleGroupOutput{Name: aws.String("some-group")}, nil)
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(remote, nil)
+		cl.EXPECT().UpdateSchedule(gomock.Any(), gomock.Any()).
+			Return(&scheduler.UpdateScheduleOutput{}, nil)
+
+		dir := t.TempDir()
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/update/normal.yml", "--history-dir", dir})
+		err := cmd.ExecuteContext(context.Background())
+		assert.NoError(err)
+
+		files, err := listHistory(dir, "some-group", "some-schedule")
+		assert.NoError(err)
+		assert.Equal([]string{filepath.Join(dir, "some-group", "some-schedule", "20240102T030405.678Z.yml")}, files)
+
+		// The snapshot is the remote schedule before updating, which can be read as schedule.yaml.
+		b, err := os.ReadFile(files[0])
+		assert.NoError(err)
+		assert.NotContains(string(b), "null")
+		schs, err := prepareInputSchedule(files[0], loadOptions{Strict: true})
+		assert.NoError(err)
+		expected, err := marshalYAMLForDiff(remote)
+		assert.NoError(err)
+		actual, err := marshalYAMLForDiff(schs[0].Input)
+		assert.NoError(err)
+		assert.Equal(expected, actual)
+	})
+
+	t.Run("update-wo-history", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+		cl.EXPECT().GetScheduleGroup(gomock.Any(), gomock.Any()).
+			Return(&scheduler.GetScheduleGroupOutput{Name: aws.String("some-group")}, nil)
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(remoteNormalForTest(), nil)
+		cl.EXPECT().UpdateSchedule(gomock.Any(), gomock.Any()).
+			Return(&scheduler.UpdateScheduleOutput{}, nil)
+
+		fn, err := filepath.Abs("testdata/update/normal.yml")
+		assert.NoError(err)
+		t.Chdir(t.TempDir())
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       bytes.NewBuffer(nil),
+		})
+		cmd.SetArgs([]string{"update", "--schedule", fn, "--history-dir", ""})
+		err = cmd.ExecuteContext(context.Background())
+		assert.NoError(err)
+		assert.NoDirExists(defaultHistoryDir)
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetScheduleGroup(gomock.Any(), gomock.Any()).
+			Return(&scheduler.GetScheduleGroupOutput{Name: aws.String("multi-group")}, nil)
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("daily"),
+			GroupName: aws.String("multi-group"),
+		}).Return(remoteDailyForTest(), nil)
+		cl.EXPECT().ListSchedules(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&scheduler.ListSchedulesOutput{
+				Schedules: []types.ScheduleSummary{
+					{GroupName: aws.String("multi-group"), Name: aws.String("daily")},
+					{GroupName: aws.String("multi-group"), Name: aws.String("stale")},
+				},
+			}, nil)
+		stale := remoteDailyForTest()
+		stale.Name = aws.String("stale")
+		gomock.InOrder(
+			cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+				Name:      aws.String("stale"),
+				GroupName: aws.String("multi-group"),
+			}).Return(stale, nil),
+			cl.EXPECT().DeleteSchedule(gomock.Any(), gomock.Any()).
+				Return(&scheduler.DeleteScheduleOutput{}, nil),
+		)
+
+		dir := t.TempDir()
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       bytes.NewBuffer(nil),
+		})
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/multi/daily.yaml", "--prune", "--history-dir", dir})
+		err := cmd.ExecuteContext(context.Background())
+		assert.NoError(err)
+
+		// The deleted schedule can be restored by rollback.
+		files, err := listHistory(dir, "multi-group", "stale")
+		assert.NoError(err)
+		if assert.Len(files, 1) {
+			schs, err := prepareInputSchedule(files[0], loadOptions{Strict: true})
+			assert.NoError(err)
+			assert.Equal("multi-group/stale", schs[0].ID())
+		}
+	})
+
+	t.Run("update-dir", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		// Snapshots in the default history dir and in --history-dir are not read as local schedules.
+		dir := t.TempDir()
+		b, err := os.ReadFile("testdata/multi/daily.yaml")
+		assert.NoError(err)
+		assert.NoError(os.WriteFile(filepath.Join(dir, "daily.yaml"), b, 0644))
+		stale := remoteDailyForTest()
+		stale.Name = aws.String("stale")
+		saveHistoryForTest(t, filepath.Join(dir, defaultHistoryDir), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), remoteDailyForTest())
+		saveHistoryForTest(t, filepath.Join(dir, "history"), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), stale)
+
+		cl.EXPECT().GetScheduleGroup(gomock.Any(), gomock.Any()).
+			Return(&scheduler.GetScheduleGroupOutput{Name: aws.String("multi-group")}, nil)
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("daily"),
+			GroupName: aws.String("multi-group"),
+		}).Return(remoteDailyForTest(), nil)
+		cl.EXPECT().ListSchedules(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&scheduler.ListSchedulesOutput{
+				Schedules: []types.ScheduleSummary{
+					{GroupName: aws.String("multi-group"), Name: aws.String("daily")},
+				},
+			}, nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       bytes.NewBuffer(nil),
+		})
+		cmd.SetArgs([]string{"update", "--schedule", dir, "--prune", "--history-dir", filepath.Join(dir, "history")})
+		err = cmd.ExecuteContext(context.Background())
+		assert.NoError(err)
+	})
+
+	t.Run("rollback-latest", func(t *testing.T) {
+		assert := assert.New(t)
+
+		dir := t.TempDir()
+		older := remoteNormalForTest()
+		older.ScheduleExpression = aws.String("cron(*/1 * * * ? *)")
+		saveHistoryForTest(t, dir, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), older)
+		latest := remoteNormalForTest()
+		latest.State = types.ScheduleStateDisabled
+		saveHistoryForTest(t, dir, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), latest)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetScheduleGroup(gomock.Any(), gomock.Any()).
+			Return(&scheduler.GetScheduleGroupOutput{Name: aws.String("some-group")}, nil)
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(remoteNormalForTest(), nil)
+		cl.EXPECT().UpdateSchedule(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, in *scheduler.UpdateScheduleInput, _ ...func(*scheduler.Options)) (*scheduler.UpdateScheduleOutput, error) {
+				assert.Equal(types.ScheduleStateDisabled, in.State)
+				assert.Equal("cron(*/5 * * * ? *)", aws.ToString(in.ScheduleExpression))
+				return &scheduler.UpdateScheduleOutput{}, nil
+			})
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		cmd.SetArgs([]string{"rollback", "--name", "some-schedule", "--group", "some-group", "--history-dir", dir})
+		err := cmd.ExecuteContext(context.Background())
+		assert.NoError(err)
+		assert.Contains(out.String(), "-State: ENABLED\n+State: DISABLED\n")
+
+		// The schedule before rollback is saved too.
+		files, err := listHistory(dir, "some-group", "some-schedule")
+		assert.NoError(err)
+		assert.Len(files, 3)
+	})
+
+	t.Run("rollback-snapshot", func(t *testing.T) {
+		assert := assert.New(t)
+
+		dir := t.TempDir()
+		older := remoteNormalForTest()
+		older.ScheduleExpression = aws.String("cron(*/1 * * * ? *)")
+		saveHistoryForTest(t, dir, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), older)
+		saveHistoryForTest(t, dir, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), remoteNormalForTest())
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		// The schedule which has been deleted is created again.
+		cl.EXPECT().GetScheduleGroup(gomock.Any(), gomock.Any()).
+			Return(&scheduler.GetScheduleGroupOutput{Name: aws.String("some-group")}, nil)
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(nil, &types.ResourceNotFoundException{})
+		cl.EXPECT().CreateSchedule(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, in *scheduler.CreateScheduleInput, _ ...func(*scheduler.Options)) (*scheduler.CreateScheduleOutput, error) {
+				assert.Equal("cron(*/1 * * * ? *)", aws.ToString(in.ScheduleExpression))
+				return &scheduler.CreateScheduleOutput{}, nil
+			})
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       bytes.NewBuffer(nil),
+		})
+		cmd.SetArgs([]string{"rollback", "--name", "some-schedule", "--group", "some-group", "--history-dir", dir,
+			"--snapshot", "20240101T000000.000Z"})
+		err := cmd.ExecuteContext(context.Background())
+		assert.NoError(err)
+	})
+
+	t.Run("err-no-snapshot", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		dir := t.TempDir()
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: mock_ebschedule.NewMockSchedulerClient(ctrl),
+			OutWriter:       bytes.NewBuffer(nil),
+		})
+		cmd.SetArgs([]string{"rollback", "--name", "some-schedule", "--group", "some-group", "--history-dir", dir})
+		err := cmd.ExecuteContext(context.Background())
+		assert.EqualError(err, "no snapshot of some-group/some-schedule in "+dir)
+	})
+
+	t.Run("err-other-schedule", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		dir := t.TempDir()
+		fn := saveHistoryForTest(t, dir, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), remoteDailyForTest())
+		assert.FileExists(fn)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: mock_ebschedule.NewMockSchedulerClient(ctrl),
+			OutWriter:       bytes.NewBuffer(nil),
+		})
+		cmd.SetArgs([]string{"rollback", "--name", "some-schedule", "--group", "some-group", "--history-dir", dir, "--snapshot", fn})
+		err := cmd.ExecuteContext(context.Background())
+		assert.EqualError(err, fn+": snapshot is of multi-group/daily, not some-group/some-schedule")
+	})
+
+	t.Run("err-snapshot-not-found", func(t *testing.T) {
+		assert := assert.New(t)
+
+		_, err := resolveSnapshot(t.TempDir(), "some-group", "some-schedule", "20240101T000000.000Z")
+		assert.ErrorIs(err, os.ErrNotExist)
+	})
+}
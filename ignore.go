@@ -0,0 +1,200 @@
+package ebschedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+)
+
+// scheduleSettingsKey is the top level key of schedule.yaml for the settings of ebschedule, which is not sent to APIs.
+const scheduleSettingsKey = "Ebschedule"
+
+// targetInputPath is the JSON pointer of Target.Input, which may be compared structurally.
+const targetInputPath = "/Target/Input"
+
+// scheduleSettings is the settings of ebschedule specified in schedule.yaml.
+type scheduleSettings struct {
+	// IgnorePaths are JSON pointers of the fields which are managed outside ebschedule.
+	// They are excluded from diff, and update keeps the remote values of them.
+	IgnorePaths []string
+}
+
+// extractScheduleSettings removes scheduleSettingsKey from the JSON converted from schedule.yaml and returns its value.
+func extractScheduleSettings(js []byte) ([]byte, *scheduleSettings, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(js, &doc); err != nil {
+		// Leave it to json.Unmarshal to the schedule to report.
+		return js, &scheduleSettings{}, nil
+	}
+	key, ok := lookupKey(doc, scheduleSettingsKey)
+	if !ok {
+		return js, &scheduleSettings{}, nil
+	}
+
+	var settings scheduleSettings
+	if err := json.Unmarshal(doc[key], &settings); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", scheduleSettingsKey, err)
+	}
+	delete(doc, key)
+
+	js, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return js, &settings, nil
+}
+
+func validateIgnorePaths(paths []string) error {
+	for _, p := range paths {
+		if !strings.HasPrefix(p, "/") {
+			return fmt.Errorf("ignore path must be a JSON pointer such as /State: %q", p)
+		}
+	}
+	return nil
+}
+
+// removeIgnoredPaths removes the fields at paths from the document.
+func removeIgnoredPaths(doc any, paths []string) (any, error) {
+	for _, p := range paths {
+		var err error
+		doc, _, err = removeValue(doc, p)
+		if err != nil {
+			return nil, fmt.Errorf("removeValue(%s): %w", p, err)
+		}
+	}
+	return doc, nil
+}
+
+// updateInputKeepingIgnored converts the local schedule to UpdateScheduleInput,
+// whose fields at ignorePaths are replaced with the ones of the remote schedule.
+// The other fields are sent as they are. Especially Target.Input is kept as the string written in schedule.yaml
+// unless the paths point into it.
+func updateInputKeepingIgnored(local *scheduler.CreateScheduleInput, remote *scheduler.GetScheduleOutput, ignorePaths []string) (*scheduler.UpdateScheduleInput, error) {
+	localDoc, err := plainScheduleDocument(local)
+	if err != nil {
+		return nil, fmt.Errorf("plainScheduleDocument.specifiedSchedule: %w", err)
+	}
+	remoteDoc, err := plainScheduleDocument(remote)
+	if err != nil {
+		return nil, fmt.Errorf("plainScheduleDocument.currentSchedule: %w", err)
+	}
+
+	var inputPaths []string
+	for _, p := range ignorePaths {
+		if strings.HasPrefix(p, targetInputPath+"/") {
+			inputPaths = append(inputPaths, strings.TrimPrefix(p, targetInputPath))
+			continue
+		}
+		localDoc, err = copyPointer(localDoc, remoteDoc, p)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(inputPaths) > 0 {
+		localDoc, err = keepIgnoredInput(localDoc, remoteDoc, inputPaths)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	js, err := json.Marshal(localDoc)
+	if err != nil {
+		return nil, err
+	}
+	var ret scheduler.UpdateScheduleInput
+	if err := json.Unmarshal(js, &ret); err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// plainScheduleDocument converts the schedule to generic document as it is, whose numbers are json.Number.
+func plainScheduleDocument(src any) (any, error) {
+	js, err := json.Marshal(src)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+	return decodeJSON(js)
+}
+
+// copyPointer sets the value of src at path to dst, or removes it from dst when src does not have it.
+func copyPointer(dst, src any, path string) (any, error) {
+	v, err := lookupPointer(src, path)
+	if err != nil {
+		// The field does not exist remotely.
+		dst, _, err = removeValue(dst, path)
+		if err != nil {
+			return nil, fmt.Errorf("removeValue(%s): %w", path, err)
+		}
+		return dst, nil
+	}
+	dst, err = setPointerWithParents(dst, path, v)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return dst, nil
+}
+
+// keepIgnoredInput replaces the fields at paths in the local Target.Input of JSON with the remote ones.
+// paths are relative to Target.Input.
+func keepIgnoredInput(localDoc, remoteDoc any, paths []string) (any, error) {
+	var localInput, remoteInput *string
+	if _, err := getValue(localDoc, targetInputPath, &localInput); err != nil {
+		return nil, fmt.Errorf("getValue(%s): %w", targetInputPath, err)
+	}
+	if _, err := getValue(remoteDoc, targetInputPath, &remoteInput); err != nil {
+		return nil, fmt.Errorf("getValue(%s): %w", targetInputPath, err)
+	}
+
+	var local, remote any
+	if localInput != nil {
+		local, _ = structuredInput(*localInput)
+	}
+	if remoteInput != nil {
+		remote, _ = structuredInput(*remoteInput)
+	}
+	if local == nil {
+		// Nothing to keep in the Input which is not JSON.
+		return localDoc, nil
+	}
+
+	for _, p := range paths {
+		var err error
+		local, err = copyPointer(local, remote, p)
+		if err != nil {
+			return nil, fmt.Errorf("%s%s: %w", targetInputPath, p, err)
+		}
+	}
+
+	js, err := json.Marshal(local)
+	if err != nil {
+		return nil, err
+	}
+	if err := setValue(localDoc, targetInputPath, string(js)); err != nil {
+		return nil, fmt.Errorf("setValue(%s): %w", targetInputPath, err)
+	}
+	return localDoc, nil
+}
+
+// setPointerWithParents sets v at path, creating missing or null objects on the way.
+func setPointerWithParents(doc any, path string, v any) (any, error) {
+	tokens := strings.Split(path, "/")
+	for i := 2; i < len(tokens); i++ {
+		parent := strings.Join(tokens[:i], "/")
+		cur, err := lookupPointer(doc, parent)
+		if err == nil && cur != nil {
+			continue
+		}
+		doc, err = addPointer(doc, parent, map[string]any{})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, err := lookupPointer(doc, path); err == nil {
+		// Replace the existing one rather than inserting into an array.
+		return doc, setValue(doc, path, v)
+	}
+	return addPointer(doc, path, v)
+}
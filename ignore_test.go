@@ -0,0 +1,206 @@
+package ebschedule
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/assert"
+	mock_ebschedule "github.com/tckz/ebschedule/mock"
+	"go.uber.org/mock/gomock"
+)
+
+func remoteIgnoreForTest() *scheduler.GetScheduleOutput {
+	remote := remoteDailyForTest()
+	remote.Description = aws.String("daily job")
+	remote.State = types.ScheduleStateDisabled
+	remote.KmsKeyArn = aws.String("arn:aws:kms:ap-northeast-1:99999:key/some-key")
+	return remote
+}
+
+func Test_ignorePath(t *testing.T) {
+	optsIgnoreUnexported := cmpopts.IgnoreUnexported(
+		scheduler.UpdateScheduleInput{},
+		types.FlexibleTimeWindow{},
+		types.Target{},
+	)
+
+	t.Run("diff", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(remoteIgnoreForTest(), nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"diff", "--schedule", "testdata/update/ignore.yml", "--exit-code"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+		assert.Equal(``, out.String())
+	})
+
+	t.Run("diff-flag", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		remote := remoteDailyForTest()
+		remote.State = types.ScheduleStateDisabled
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(remote, nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"diff", "--schedule", "testdata/multi/daily.yaml", "--ignore-path", "/State", "--exit-code"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+		assert.Equal(``, out.String())
+	})
+
+	t.Run("update", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		remote := remoteIgnoreForTest()
+		remote.Description = aws.String("old description")
+		cl.EXPECT().GetScheduleGroup(gomock.Any(), gomock.Any()).Return(&scheduler.GetScheduleGroupOutput{}, nil)
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(remote, nil)
+		cl.EXPECT().UpdateSchedule(gomock.Any(), CmpDiff(&scheduler.UpdateScheduleInput{
+			FlexibleTimeWindow: &types.FlexibleTimeWindow{Mode: types.FlexibleTimeWindowModeOff},
+			GroupName:          aws.String("multi-group"),
+			Name:               aws.String("daily"),
+			Description:        aws.String("daily job"),
+			ScheduleExpression: aws.String("cron(0 3 * * ? *)"),
+			// Remote values are kept.
+			State:     types.ScheduleStateDisabled,
+			KmsKeyArn: aws.String("arn:aws:kms:ap-northeast-1:99999:key/some-key"),
+			Target: &types.Target{
+				Arn:     aws.String("arn:aws:lambda:ap-northeast-1:99999:function:some-func"),
+				RoleArn: aws.String("arn:aws:iam::99999:role/some-scheduler-role"),
+			},
+		}, optsIgnoreUnexported)).Return(&scheduler.UpdateScheduleOutput{
+			ScheduleArn: aws.String("arn:aws:scheduler:ap-northeast-1:99999:schedule/multi-group/daily"),
+		}, nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/update/ignore.yml", "--history-dir", t.TempDir()})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+	})
+
+	t.Run("err-path", func(t *testing.T) {
+		assert := assert.New(t)
+
+		_, err := prepareInputSchedule("testdata/update/ignore.yml", loadOptions{Strict: true, IgnorePaths: []string{"State"}})
+		assert.EqualError(err, `ignore path must be a JSON pointer such as /State: "State"`)
+	})
+}
+
+func Test_updateInputKeepingIgnored(t *testing.T) {
+	assert := assert.New(t)
+
+	local := &scheduler.CreateScheduleInput{
+		Name:               aws.String("daily"),
+		ScheduleExpression: aws.String("cron(0 3 * * ? *)"),
+		Target: &types.Target{
+			Arn:   aws.String("arn"),
+			Input: aws.String(`{"b":1,"a":2}`),
+		},
+	}
+	remote := &scheduler.GetScheduleOutput{
+		Name:               aws.String("daily"),
+		ScheduleExpression: aws.String("cron(0 4 * * ? *)"),
+		Description:        aws.String("remote"),
+		Target: &types.Target{
+			Arn:         aws.String("arn"),
+			Input:       aws.String(`{"b":3}`),
+			RetryPolicy: &types.RetryPolicy{MaximumRetryAttempts: aws.Int32(3)},
+		},
+	}
+
+	got, err := updateInputKeepingIgnored(local, remote, []string{
+		"/Description",
+		"/Target/Input/b",
+		"/Target/RetryPolicy/MaximumRetryAttempts",
+		"/Target/DeadLetterConfig/Arn",
+	})
+	assert.NoError(err)
+	assert.Equal(&scheduler.UpdateScheduleInput{
+		Name:               aws.String("daily"),
+		ScheduleExpression: aws.String("cron(0 3 * * ? *)"),
+		Description:        aws.String("remote"),
+		Target: &types.Target{
+			Arn:         aws.String("arn"),
+			Input:       aws.String(`{"a":2,"b":3}`),
+			RetryPolicy: &types.RetryPolicy{MaximumRetryAttempts: aws.Int32(3)},
+		},
+	}, got)
+}
+
+func Test_updateInputKeepingIgnored_input(t *testing.T) {
+	assert := assert.New(t)
+
+	local := &scheduler.CreateScheduleInput{
+		Name:        aws.String("daily"),
+		Description: aws.String("local"),
+		Target: &types.Target{
+			Arn:   aws.String("arn"),
+			Input: aws.String(`{"z": 1.50, "orderId": 12345678901234567891}`),
+		},
+	}
+	remote := &scheduler.GetScheduleOutput{
+		Name:        aws.String("daily"),
+		Description: aws.String("remote"),
+		Target: &types.Target{
+			Arn:   aws.String("arn"),
+			Input: aws.String(`{"orderId":1}`),
+		},
+	}
+
+	// Target.Input is sent as it is written when no path points into it.
+	got, err := updateInputKeepingIgnored(local, remote, []string{"/Description"})
+	assert.NoError(err)
+	assert.Equal("remote", aws.ToString(got.Description))
+	assert.Equal(`{"z": 1.50, "orderId": 12345678901234567891}`, aws.ToString(got.Target.Input))
+
+	got, err = updateInputKeepingIgnored(local, remote, []string{"/Target/Input/orderId"})
+	assert.NoError(err)
+	assert.Equal("local", aws.ToString(got.Description))
+	assert.Equal(`{"orderId":1,"z":1.50}`, aws.ToString(got.Target.Input))
+}
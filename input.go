@@ -0,0 +1,94 @@
+package ebschedule
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// inputObjectKey is the key of Target, which is an alternative to Input to write it as native YAML.
+const inputObjectKey = "InputObject"
+
+// expandStructuredInput serializes Target.Input given as mapping or sequence, or Target.InputObject, to compact JSON string.
+// js is the JSON converted from schedule.yaml.
+func expandStructuredInput(js []byte) ([]byte, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(js, &doc); err != nil {
+		// Leave it to json.Unmarshal to the schedule to report.
+		return js, nil
+	}
+	targetKey, ok := lookupKey(doc, "Target")
+	if !ok {
+		return js, nil
+	}
+	var target map[string]json.RawMessage
+	if err := json.Unmarshal(doc[targetKey], &target); err != nil {
+		return js, nil
+	}
+
+	inputKey, hasInput := lookupKey(target, "Input")
+	objKey, hasObj := lookupKey(target, inputObjectKey)
+	var raw json.RawMessage
+	switch {
+	case hasInput && hasObj:
+		return nil, fmt.Errorf("Target.Input and Target.%s cannot be specified together", inputObjectKey)
+	case hasObj:
+		raw = target[objKey]
+		delete(target, objKey)
+		inputKey = "Input"
+	case hasInput:
+		raw = target[inputKey]
+		if t := bytes.TrimSpace(raw); len(t) == 0 || (t[0] != '{' && t[0] != '[') {
+			return js, nil
+		}
+	default:
+		return js, nil
+	}
+
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, raw); err != nil {
+		return nil, fmt.Errorf("json.Compact: %w", err)
+	}
+	s, err := json.Marshal(buf.String())
+	if err != nil {
+		return nil, err
+	}
+	target[inputKey] = s
+
+	if doc[targetKey], err = json.Marshal(target); err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// lookupKey finds the key case-insensitively as encoding/json does, preferring an exact match.
+func lookupKey(m map[string]json.RawMessage, key string) (string, bool) {
+	if _, ok := m[key]; ok {
+		return key, true
+	}
+	for k := range m {
+		if strings.EqualFold(k, key) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// structuredInput parses Input into generic value when it is JSON object or array.
+// Numbers are kept as json.Number, so that they are compared by their text and are written as they are
+// even if they are beyond the precision of float64.
+func structuredInput(input string) (any, bool) {
+	t := strings.TrimSpace(input)
+	if t == "" || (t[0] != '{' && t[0] != '[') {
+		return nil, false
+	}
+
+	dec := json.NewDecoder(strings.NewReader(t))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return nil, false
+	}
+	return v, true
+}
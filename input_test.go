@@ -0,0 +1,73 @@
+package ebschedule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_expandStructuredInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "mapping",
+			src:  `{"Name":"a","Target":{"Arn":"arn","Input":{"b":1,"a":[true,"x"]}}}`,
+			want: `{"Name":"a","Target":{"Arn":"arn","Input":"{\"b\":1,\"a\":[true,\"x\"]}"}}`,
+		},
+		{
+			name: "sequence",
+			src:  `{"target":{"input":[1,2]}}`,
+			want: `{"target":{"input":"[1,2]"}}`,
+		},
+		{
+			name: "InputObject",
+			src:  `{"Target":{"Arn":"arn","InputObject":{"key":"value"}}}`,
+			want: `{"Target":{"Arn":"arn","Input":"{\"key\":\"value\"}"}}`,
+		},
+		{
+			name: "string",
+			src:  `{"Target":{"Input":"{\"key\": \"value\"}"}}`,
+			want: `{"Target":{"Input":"{\"key\": \"value\"}"}}`,
+		},
+		{
+			name: "without Target",
+			src:  `{"Name":"a"}`,
+			want: `{"Name":"a"}`,
+		},
+		{
+			name:    "both",
+			src:     `{"Target":{"Input":"{}","InputObject":{}}}`,
+			wantErr: `Target.Input and Target.InputObject cannot be specified together`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := expandStructuredInput([]byte(tt.src))
+			if tt.wantErr != "" {
+				assert.EqualError(err, tt.wantErr)
+				return
+			}
+			if assert.NoError(err) {
+				assert.JSONEq(tt.want, string(got))
+			}
+		})
+	}
+}
+
+func Test_structuredInput(t *testing.T) {
+	assert := assert.New(t)
+
+	v, ok := structuredInput(`{"orderId":12345678901234567891,"price":1.50}`)
+	assert.True(ok)
+
+	// Numbers are written as they are without losing precision.
+	y, err := marshalScheduleYAML(v)
+	assert.NoError(err)
+	assert.Equal("orderId: 12345678901234567891\nprice: 1.50\n", y)
+}
@@ -0,0 +1,87 @@
+package ebschedule
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// jsonPatchOperation is an operation of RFC 6902 JSON Patch.
+type jsonPatchOperation struct {
+	Op    string
+	Path  string
+	Value any
+}
+
+func (o jsonPatchOperation) MarshalJSON() ([]byte, error) {
+	if o.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+	return json.Marshal(struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}{o.Op, o.Path, o.Value})
+}
+
+// computeJSONPatch returns operations which convert from to to.
+// Both must be generic values such as decoded by encoding/json.
+// Arrays of different length are replaced as a whole.
+func computeJSONPatch(from, to any) []jsonPatchOperation {
+	if from == nil && to != nil {
+		return []jsonPatchOperation{{Op: "add", Path: "", Value: to}}
+	}
+	ops := []jsonPatchOperation{}
+	return appendJSONPatch(ops, "", from, to)
+}
+
+func appendJSONPatch(ops []jsonPatchOperation, path string, from, to any) []jsonPatchOperation {
+	switch f := from.(type) {
+	case map[string]any:
+		t, ok := to.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := lo.Union(lo.Keys(f), lo.Keys(t))
+		slices.Sort(keys)
+		for _, k := range keys {
+			p := path + "/" + escapeJSONPointer(k)
+			fv, inFrom := f[k]
+			tv, inTo := t[k]
+			switch {
+			case !inTo:
+				ops = append(ops, jsonPatchOperation{Op: "remove", Path: p})
+			case !inFrom:
+				ops = append(ops, jsonPatchOperation{Op: "add", Path: p, Value: tv})
+			default:
+				ops = appendJSONPatch(ops, p, fv, tv)
+			}
+		}
+		return ops
+	case []any:
+		t, ok := to.([]any)
+		if !ok || len(f) != len(t) {
+			break
+		}
+		for i := range f {
+			ops = appendJSONPatch(ops, path+"/"+strconv.Itoa(i), f[i], t[i])
+		}
+		return ops
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		ops = append(ops, jsonPatchOperation{Op: "replace", Path: path, Value: to})
+	}
+	return ops
+}
+
+func escapeJSONPointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
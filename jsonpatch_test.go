@@ -0,0 +1,64 @@
+package ebschedule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_computeJSONPatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     any
+		to       any
+		expected []jsonPatchOperation
+	}{
+		{
+			name:     "same",
+			from:     map[string]any{"a": "x", "b": []any{"1", "2"}},
+			to:       map[string]any{"a": "x", "b": []any{"1", "2"}},
+			expected: []jsonPatchOperation{},
+		},
+		{
+			name: "add-remove-replace",
+			from: map[string]any{"a": "x", "b": map[string]any{"c": "y"}, "d": "z"},
+			to:   map[string]any{"a": "x2", "b": map[string]any{"c": "y", "e/f": "w"}},
+			expected: []jsonPatchOperation{
+				{Op: "replace", Path: "/a", Value: "x2"},
+				{Op: "add", Path: "/b/e~1f", Value: "w"},
+				{Op: "remove", Path: "/d"},
+			},
+		},
+		{
+			name: "array",
+			from: map[string]any{"a": []any{"1", "2"}, "b": []any{"1"}},
+			to:   map[string]any{"a": []any{"1", "3"}, "b": []any{"1", "2"}},
+			expected: []jsonPatchOperation{
+				{Op: "replace", Path: "/a/1", Value: "3"},
+				{Op: "replace", Path: "/b", Value: []any{"1", "2"}},
+			},
+		},
+		{
+			name: "type-changed",
+			from: map[string]any{"a": nil},
+			to:   map[string]any{"a": map[string]any{"b": "c"}},
+			expected: []jsonPatchOperation{
+				{Op: "replace", Path: "/a", Value: map[string]any{"b": "c"}},
+			},
+		},
+		{
+			name: "from-nil",
+			from: nil,
+			to:   map[string]any{"a": "x"},
+			expected: []jsonPatchOperation{
+				{Op: "add", Path: "", Value: map[string]any{"a": "x"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, computeJSONPatch(tt.from, tt.to))
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package ebschedule
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	outputFormatTable = "table"
+	outputFormatText  = "text"
+	outputFormatJSON  = "json"
+	outputFormatYAML  = "yaml"
+)
+
+type scheduleListItem struct {
+	GroupName                  string
+	Name                       string
+	State                      types.ScheduleState
+	ScheduleExpression         string
+	ScheduleExpressionTimezone string
+	// Next is the next fire time. nil if the schedule is disabled or never fires again.
+	Next      *time.Time `json:",omitempty"`
+	TargetArn string
+}
+
+func newListCommand(in *CommandInput) *cobra.Command {
+	return wrapCobra(&cobra.Command{
+		Use:   "list",
+		Short: "List remote schedules",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			optGroup, _ := cmd.Flags().GetString(OptGroup)
+			optNamePrefix, _ := cmd.Flags().GetString(OptNamePrefix)
+			optOutput, _ := cmd.Flags().GetString(OptOutput)
+
+			switch optOutput {
+			case outputFormatTable, outputFormatJSON, outputFormatYAML:
+			default:
+				return fmt.Errorf("unknown output format: %s", optOutput)
+			}
+
+			items, err := listScheduleItems(ctx, in.SchedulerClient, optGroup, optNamePrefix)
+			if err != nil {
+				return err
+			}
+
+			switch optOutput {
+			case outputFormatJSON:
+				enc := json.NewEncoder(in.OutWriter)
+				enc.SetIndent("", "  ")
+				return enc.Encode(items)
+			case outputFormatYAML:
+				return outputResultAsYAML(items, in.OutWriter)
+			}
+			return outputScheduleListAsTable(items, in.OutWriter)
+		},
+	}, func(cmd *cobra.Command) {
+		cmd.Flags().String(OptGroup, "", "name of the schedule group. all groups if omitted")
+		cmd.Flags().String(OptNamePrefix, "", "list only schedules whose name starts with the prefix")
+		cmd.Flags().StringP(OptOutput, "o", outputFormatTable, "output format: table, json or yaml")
+	})
+}
+
+func listScheduleItems(ctx context.Context, client SchedulerClient, groupName, namePrefix string) ([]scheduleListItem, error) {
+	now := timeNow()
+	params := &scheduler.ListSchedulesInput{}
+	if groupName != "" {
+		params.GroupName = aws.String(groupName)
+	}
+	if namePrefix != "" {
+		params.NamePrefix = aws.String(namePrefix)
+	}
+	sums, err := listSchedules(ctx, client, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// ScheduleSummary does not have ScheduleExpression, so get each schedule.
+	items := make([]scheduleListItem, 0, len(sums))
+	for _, sum := range sums {
+		sch, err := client.GetSchedule(ctx, &scheduler.GetScheduleInput{
+			Name:      sum.Name,
+			GroupName: sum.GroupName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: scheduler.GetSchedule: %w", scheduleID(sum.GroupName, sum.Name), err)
+		}
+
+		item := scheduleListItem{
+			GroupName:                  aws.ToString(sch.GroupName),
+			Name:                       aws.ToString(sch.Name),
+			State:                      sch.State,
+			ScheduleExpression:         aws.ToString(sch.ScheduleExpression),
+			ScheduleExpressionTimezone: aws.ToString(sch.ScheduleExpressionTimezone),
+		}
+		if sch.Target != nil {
+			item.TargetArn = aws.ToString(sch.Target.Arn)
+		}
+		if sch.State != types.ScheduleStateDisabled {
+			next, err := nextFireTime(sch, now)
+			if err != nil {
+				// The schedule is listed anyway.
+				log.Printf("%s: %v", scheduleID(sch.GroupName, sch.Name), err)
+			}
+			item.Next = next
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// nextFireTime returns the next fire time of the remote schedule after now.
+func nextFireTime(sch *scheduler.GetScheduleOutput, now time.Time) (*time.Time, error) {
+	t, err := remoteToNextTarget(sch)
+	if err != nil {
+		return nil, fmt.Errorf("remoteToNextTarget: %w", err)
+	}
+	times, err := nextFireTimes(t.Schedule, t.Anchor, now, 1)
+	if err != nil || len(times) == 0 {
+		return nil, err
+	}
+	return &times[0], nil
+}
+
+func outputScheduleListAsTable(items []scheduleListItem, w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "GROUP\tNAME\tSTATE\tEXPRESSION\tTIMEZONE\tNEXT\tTARGET")
+	for _, e := range items {
+		next := "-"
+		if e.Next != nil {
+			next = e.Next.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.GroupName, e.Name, e.State, e.ScheduleExpression, e.ScheduleExpressionTimezone, next, e.TargetArn)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,125 @@
+package ebschedule
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/assert"
+	mock_ebschedule "github.com/tckz/ebschedule/mock"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_list(t *testing.T) {
+	defer func(f func() time.Time) { timeNow = f }(timeNow)
+	timeNow = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	setup := func(t *testing.T, ctrl *gomock.Controller) *mock_ebschedule.MockSchedulerClient {
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+		cl.EXPECT().ListSchedules(gomock.Any(), CmpDiff(&scheduler.ListSchedulesInput{
+			GroupName:  aws.String("some-group"),
+			NamePrefix: aws.String("some-"),
+		}, cmpopts.IgnoreUnexported(scheduler.ListSchedulesInput{})), gomock.Any()).
+			Return(&scheduler.ListSchedulesOutput{
+				Schedules: []types.ScheduleSummary{
+					{GroupName: aws.String("some-group"), Name: aws.String("some-schedule")},
+				},
+			}, nil)
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("some-schedule"),
+			GroupName: aws.String("some-group"),
+		}).Return(remoteScheduleForTest(), nil)
+		return cl
+	}
+
+	t.Run("table", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: setup(t, ctrl),
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"list", "--group", "some-group", "--name-prefix", "some-"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+		assert.Equal(`GROUP       NAME           STATE    EXPRESSION           TIMEZONE    NEXT                       TARGET
+some-group  some-schedule  ENABLED  cron(*/3 * * * ? *)  Asia/Tokyo  2024-01-02T12:06:00+09:00  arn:aws:lambda:ap-northeast-1:99999:function:some-func
+`, out.String())
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: setup(t, ctrl),
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"list", "--group", "some-group", "--name-prefix", "some-", "-o", "yaml"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+		assert.Equal(`---
+- GroupName: some-group
+  Name: some-schedule
+  State: ENABLED
+  ScheduleExpression: cron(*/3 * * * ? *)
+  ScheduleExpressionTimezone: Asia/Tokyo
+  Next: "2024-01-02T12:06:00+09:00"
+  TargetArn: arn:aws:lambda:ap-northeast-1:99999:function:some-func
+`, out.String())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+		cl.EXPECT().ListSchedules(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&scheduler.ListSchedulesOutput{
+				Schedules: []types.ScheduleSummary{
+					{GroupName: aws.String("multi-group"), Name: aws.String("daily")},
+				},
+			}, nil)
+		remote := remoteDailyForTest()
+		remote.State = types.ScheduleStateDisabled
+		remote.ScheduleExpressionTimezone = aws.String("UTC")
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(remote, nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		cmd.SetArgs([]string{"list"})
+		err := cmd.ExecuteContext(context.Background())
+
+		assert.NoError(err)
+		assert.Equal(`GROUP        NAME   STATE     EXPRESSION         TIMEZONE  NEXT  TARGET
+multi-group  daily  DISABLED  cron(0 3 * * ? *)  UTC       -     arn:aws:lambda:ap-northeast-1:99999:function:some-func
+`, out.String())
+	})
+}
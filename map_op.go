@@ -6,6 +6,7 @@ import (
 	"github.com/mattn/go-jsonpointer"
 )
 
+// getValue sets the value at path to dst. dst is set to nil if the value is null.
 func getValue[T any](s any, path string, dst **T) (found bool, err error) {
 	if !jsonpointer.Has(s, path) {
 		return false, nil
@@ -17,6 +18,10 @@ func getValue[T any](s any, path string, dst **T) (found bool, err error) {
 	}
 
 	*dst = nil
+	if val == nil {
+		// null
+		return true, nil
+	}
 	v, ok := val.(T)
 	if !ok {
 		return true, fmt.Errorf("type mismatch: val=%T, dst=%T", val, *dst)
@@ -33,6 +33,19 @@ func Test_getValue(t *testing.T) {
 		assert.Equal(11, *v)
 	})
 
+	t.Run("null", func(t *testing.T) {
+		assert := assert.New(t)
+		s := map[string]interface{}{
+			"key": nil,
+		}
+
+		var v *string
+		found, err := getValue(s, "/key", &v)
+		assert.True(found)
+		assert.NoError(err)
+		assert.Nil(v)
+	})
+
 	t.Run("not found", func(t *testing.T) {
 		assert := assert.New(t)
 		s := map[string]interface{}{
@@ -81,6 +81,46 @@ func (mr *MockSchedulerClientMockRecorder) CreateScheduleGroup(ctx, params any,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduleGroup", reflect.TypeOf((*MockSchedulerClient)(nil).CreateScheduleGroup), varargs...)
 }
 
+// DeleteSchedule mocks base method.
+func (m *MockSchedulerClient) DeleteSchedule(ctx context.Context, params *scheduler.DeleteScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.DeleteScheduleOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteSchedule", varargs...)
+	ret0, _ := ret[0].(*scheduler.DeleteScheduleOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSchedule indicates an expected call of DeleteSchedule.
+func (mr *MockSchedulerClientMockRecorder) DeleteSchedule(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSchedule", reflect.TypeOf((*MockSchedulerClient)(nil).DeleteSchedule), varargs...)
+}
+
+// DeleteScheduleGroup mocks base method.
+func (m *MockSchedulerClient) DeleteScheduleGroup(ctx context.Context, params *scheduler.DeleteScheduleGroupInput, optFns ...func(*scheduler.Options)) (*scheduler.DeleteScheduleGroupOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteScheduleGroup", varargs...)
+	ret0, _ := ret[0].(*scheduler.DeleteScheduleGroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteScheduleGroup indicates an expected call of DeleteScheduleGroup.
+func (mr *MockSchedulerClientMockRecorder) DeleteScheduleGroup(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduleGroup", reflect.TypeOf((*MockSchedulerClient)(nil).DeleteScheduleGroup), varargs...)
+}
+
 // GetSchedule mocks base method.
 func (m *MockSchedulerClient) GetSchedule(ctx context.Context, params *scheduler.GetScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.GetScheduleOutput, error) {
 	m.ctrl.T.Helper()
@@ -121,6 +161,46 @@ func (mr *MockSchedulerClientMockRecorder) GetScheduleGroup(ctx, params any, opt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduleGroup", reflect.TypeOf((*MockSchedulerClient)(nil).GetScheduleGroup), varargs...)
 }
 
+// ListScheduleGroups mocks base method.
+func (m *MockSchedulerClient) ListScheduleGroups(ctx context.Context, params *scheduler.ListScheduleGroupsInput, optFns ...func(*scheduler.Options)) (*scheduler.ListScheduleGroupsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListScheduleGroups", varargs...)
+	ret0, _ := ret[0].(*scheduler.ListScheduleGroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduleGroups indicates an expected call of ListScheduleGroups.
+func (mr *MockSchedulerClientMockRecorder) ListScheduleGroups(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduleGroups", reflect.TypeOf((*MockSchedulerClient)(nil).ListScheduleGroups), varargs...)
+}
+
+// ListSchedules mocks base method.
+func (m *MockSchedulerClient) ListSchedules(ctx context.Context, params *scheduler.ListSchedulesInput, optFns ...func(*scheduler.Options)) (*scheduler.ListSchedulesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListSchedules", varargs...)
+	ret0, _ := ret[0].(*scheduler.ListSchedulesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSchedules indicates an expected call of ListSchedules.
+func (mr *MockSchedulerClientMockRecorder) ListSchedules(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSchedules", reflect.TypeOf((*MockSchedulerClient)(nil).ListSchedules), varargs...)
+}
+
 // UpdateSchedule mocks base method.
 func (m *MockSchedulerClient) UpdateSchedule(ctx context.Context, params *scheduler.UpdateScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.UpdateScheduleOutput, error) {
 	m.ctrl.T.Helper()
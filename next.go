@@ -0,0 +1,195 @@
+package ebschedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/spf13/cobra"
+)
+
+// timeNow is replaceable for testing.
+var timeNow = time.Now
+
+func newNextCommand(in *CommandInput) *cobra.Command {
+	return wrapCobra(&cobra.Command{
+		Use:   "next",
+		Short: "Show upcoming fire times of schedule",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			patterns, _ := cmd.Flags().GetStringArray(OptSchedule)
+			optName, _ := cmd.Flags().GetString(OptName)
+			optGroup, _ := cmd.Flags().GetString(OptGroup)
+			optCount, _ := cmd.Flags().GetInt(OptCount)
+
+			if optCount <= 0 {
+				return fmt.Errorf("--%s must be greater than 0", OptCount)
+			}
+
+			var targets []*nextTarget
+			switch {
+			case len(patterns) > 0 && optName != "":
+				return fmt.Errorf("--%s and --%s cannot be used together", OptSchedule, OptName)
+			case len(patterns) > 0:
+				schs, err := prepareInputSchedules(patterns, loadOptionsFromFlags(cmd))
+				if err != nil {
+					return fmt.Errorf("prepareInputSchedules: %w", err)
+				}
+				for _, sch := range schs {
+					targets = append(targets, &nextTarget{Schedule: sch.Input})
+				}
+			case optName != "":
+				t, err := remoteNextTarget(ctx, in.SchedulerClient, optGroup, optName)
+				if err != nil {
+					return err
+				}
+				targets = append(targets, t)
+			default:
+				return fmt.Errorf("either --%s or --%s must be specified", OptSchedule, OptName)
+			}
+
+			now := timeNow()
+			var errs []error
+			for _, t := range targets {
+				id := scheduleID(t.Schedule.GroupName, t.Schedule.Name)
+				times, err := nextFireTimes(t.Schedule, t.Anchor, now, optCount)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", id, err))
+					continue
+				}
+				if t.Schedule.State == types.ScheduleStateDisabled {
+					log.Printf("Schedule %s is DISABLED, it does not fire until enabled", id)
+				}
+				writeNextFireTimes(in.OutWriter, t.Schedule, times)
+			}
+			return errors.Join(errs...)
+		},
+	}, func(cmd *cobra.Command) {
+		addOptionalScheduleFlag(cmd)
+		cmd.Flags().String(OptName, "", "name of the remote schedule")
+		cmd.Flags().String(OptGroup, "default", "name of the schedule group")
+		cmd.Flags().IntP(OptCount, "n", 10, "number of fire times to show")
+	})
+}
+
+type nextTarget struct {
+	Schedule *scheduler.CreateScheduleInput
+	// Anchor is the time which rate() counts from when StartDate is not specified, i.e. creation date of the remote schedule.
+	// nil means now.
+	Anchor *time.Time
+}
+
+func remoteNextTarget(ctx context.Context, client SchedulerClient, group, name string) (*nextTarget, error) {
+	out, err := client.GetSchedule(ctx, &scheduler.GetScheduleInput{
+		Name:      aws.String(name),
+		GroupName: aws.String(group),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scheduler.GetSchedule: %w", err)
+	}
+	return remoteToNextTarget(out)
+}
+
+// remoteToNextTarget converts the remote schedule to nextTarget, whose Anchor is CreationDate.
+func remoteToNextTarget(out *scheduler.GetScheduleOutput) (*nextTarget, error) {
+	b, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	var sch scheduler.CreateScheduleInput
+	if err := json.Unmarshal(b, &sch); err != nil {
+		return nil, err
+	}
+	return &nextTarget{Schedule: &sch, Anchor: out.CreationDate}, nil
+}
+
+// nextFireTimes returns at most n fire times of the schedule after now.
+// StartDate and EndDate are applied to recurring schedules, as EventBridge Scheduler ignores them for one-time schedules.
+func nextFireTimes(sch *scheduler.CreateScheduleInput, anchor *time.Time, now time.Time, n int) ([]time.Time, error) {
+	if sch.ScheduleExpression == nil {
+		return nil, fmt.Errorf("ScheduleExpression must be specified")
+	}
+	e, err := parseScheduleExpression(*sch.ScheduleExpression)
+	if err != nil {
+		return nil, fmt.Errorf("ScheduleExpression: %w", err)
+	}
+	loc, err := time.LoadLocation(aws.ToString(sch.ScheduleExpressionTimezone))
+	if err != nil {
+		return nil, fmt.Errorf("ScheduleExpressionTimezone: %w", err)
+	}
+
+	now = now.In(loc)
+	if at, ok := e.(*atExpression); ok {
+		t := time.Date(at.At.Year(), at.At.Month(), at.At.Day(), at.At.Hour(), at.At.Minute(), at.At.Second(), 0, loc)
+		if t.After(now) {
+			return []time.Time{t}, nil
+		}
+		return nil, nil
+	}
+
+	// Fire times are strictly after `after`.
+	after := now
+	if sch.StartDate != nil && sch.StartDate.After(now) {
+		after = sch.StartDate.In(loc).Add(-time.Nanosecond)
+	}
+
+	var next func(time.Time) (time.Time, bool)
+	switch e := e.(type) {
+	case *cronExpression:
+		next = e.next
+	case *rateExpression:
+		base := now
+		switch {
+		case sch.StartDate != nil:
+			base = *sch.StartDate
+		case anchor != nil:
+			base = *anchor
+		}
+		base = base.In(loc)
+		next = func(after time.Time) (time.Time, bool) {
+			if after.Before(base) {
+				return base, true
+			}
+			k := after.Sub(base)/e.Every + 1
+			return base.Add(k * e.Every), true
+		}
+	}
+
+	var ret []time.Time
+	for len(ret) < n {
+		t, ok := next(after)
+		if !ok || (sch.EndDate != nil && !t.Before(*sch.EndDate)) {
+			break
+		}
+		ret = append(ret, t)
+		after = t
+	}
+	return ret, nil
+}
+
+func writeNextFireTimes(w io.Writer, sch *scheduler.CreateScheduleInput, times []time.Time) {
+	tz := aws.ToString(sch.ScheduleExpressionTimezone)
+	if tz == "" {
+		tz = "UTC"
+	}
+	fmt.Fprintf(w, "# %s %s %s\n", scheduleID(sch.GroupName, sch.Name), aws.ToString(sch.ScheduleExpression), tz)
+	if len(times) == 0 {
+		fmt.Fprintln(w, "(no upcoming fire times)")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LOCAL\tWEEKDAY\tUTC")
+	for _, t := range times {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", t.Format(time.RFC3339), t.Format("Mon"), t.UTC().Format(time.RFC3339))
+	}
+	_ = tw.Flush()
+}
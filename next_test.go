@@ -0,0 +1,250 @@
+package ebschedule
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/stretchr/testify/assert"
+	mock_ebschedule "github.com/tckz/ebschedule/mock"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_nextFireTimes(t *testing.T) {
+	now := time.Date(2024, 1, 30, 12, 34, 56, 0, time.UTC)
+	date := func(s string) *time.Time {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			panic(err)
+		}
+		return &t
+	}
+
+	tests := []struct {
+		name   string
+		sch    *scheduler.CreateScheduleInput
+		anchor *time.Time
+		n      int
+		want   []string
+	}{
+		{
+			name: "cron",
+			sch:  &scheduler.CreateScheduleInput{ScheduleExpression: aws.String("cron(*/20 12-13 * * ? *)")},
+			n:    4,
+			want: []string{"2024-01-30T12:40:00Z", "2024-01-30T13:00:00Z", "2024-01-30T13:20:00Z", "2024-01-30T13:40:00Z"},
+		},
+		{
+			name: "cron timezone",
+			sch: &scheduler.CreateScheduleInput{
+				ScheduleExpression:         aws.String("cron(0 3 * * ? *)"),
+				ScheduleExpressionTimezone: aws.String("Asia/Tokyo"),
+			},
+			n:    2,
+			want: []string{"2024-01-31T03:00:00+09:00", "2024-02-01T03:00:00+09:00"},
+		},
+		{
+			name: "cron last day of month",
+			sch:  &scheduler.CreateScheduleInput{ScheduleExpression: aws.String("cron(0 0 L * ? *)")},
+			n:    3,
+			want: []string{"2024-01-31T00:00:00Z", "2024-02-29T00:00:00Z", "2024-03-31T00:00:00Z"},
+		},
+		{
+			name: "cron last weekday of month",
+			sch:  &scheduler.CreateScheduleInput{ScheduleExpression: aws.String("cron(0 0 LW * ? *)")},
+			n:    2,
+			want: []string{"2024-01-31T00:00:00Z", "2024-02-29T00:00:00Z"},
+		},
+		{
+			name: "cron nearest weekday",
+			sch: &scheduler.CreateScheduleInput{
+				ScheduleExpression: aws.String("cron(0 0 1W * ? *)"),
+				StartDate:          date("2024-06-01T00:00:00Z"),
+			},
+			n: 2,
+			// 2024-06-01 is Saturday, so the nearest weekday in the month is Monday 3rd.
+			want: []string{"2024-06-03T00:00:00Z", "2024-07-01T00:00:00Z"},
+		},
+		{
+			name: "cron nth weekday",
+			sch:  &scheduler.CreateScheduleInput{ScheduleExpression: aws.String("cron(0 9 ? * MON#2 *)")},
+			n:    2,
+			want: []string{"2024-02-12T09:00:00Z", "2024-03-11T09:00:00Z"},
+		},
+		{
+			name: "cron last friday",
+			sch:  &scheduler.CreateScheduleInput{ScheduleExpression: aws.String("cron(0 9 ? * 6L *)")},
+			n:    2,
+			want: []string{"2024-02-23T09:00:00Z", "2024-03-29T09:00:00Z"},
+		},
+		{
+			name: "cron year",
+			sch:  &scheduler.CreateScheduleInput{ScheduleExpression: aws.String("cron(0 0 1 1 ? 2024,2026)")},
+			n:    10,
+			want: []string{"2026-01-01T00:00:00Z"},
+		},
+		{
+			name: "cron start and end",
+			sch: &scheduler.CreateScheduleInput{
+				ScheduleExpression: aws.String("cron(0 0 * * ? *)"),
+				StartDate:          date("2024-03-01T00:00:00Z"),
+				EndDate:            date("2024-03-03T00:00:00Z"),
+			},
+			n:    10,
+			want: []string{"2024-03-01T00:00:00Z", "2024-03-02T00:00:00Z"},
+		},
+		{
+			name: "cron spring-forward",
+			sch: &scheduler.CreateScheduleInput{
+				ScheduleExpression:         aws.String("cron(30 2 * * ? *)"),
+				ScheduleExpressionTimezone: aws.String("America/New_York"),
+				StartDate:                  date("2024-03-09T12:00:00Z"),
+			},
+			n:    2,
+			want: []string{"2024-03-11T02:30:00-04:00", "2024-03-12T02:30:00-04:00"},
+		},
+		{
+			name: "cron fall-back",
+			sch: &scheduler.CreateScheduleInput{
+				ScheduleExpression:         aws.String("cron(30 1 * * ? *)"),
+				ScheduleExpressionTimezone: aws.String("America/New_York"),
+				StartDate:                  date("2024-11-02T12:00:00Z"),
+			},
+			n:    2,
+			want: []string{"2024-11-03T01:30:00-04:00", "2024-11-04T01:30:00-05:00"},
+		},
+		{
+			name: "cron fall-back hourly",
+			sch: &scheduler.CreateScheduleInput{
+				ScheduleExpression:         aws.String("cron(30 * * * ? *)"),
+				ScheduleExpressionTimezone: aws.String("America/New_York"),
+				StartDate:                  date("2024-11-03T04:45:00Z"),
+			},
+			n:    2,
+			want: []string{"2024-11-03T01:30:00-04:00", "2024-11-03T02:30:00-05:00"},
+		},
+		{
+			name:   "rate anchor",
+			sch:    &scheduler.CreateScheduleInput{ScheduleExpression: aws.String("rate(5 hours)")},
+			anchor: date("2024-01-30T00:10:00Z"),
+			n:      2,
+			want:   []string{"2024-01-30T15:10:00Z", "2024-01-30T20:10:00Z"},
+		},
+		{
+			name: "rate start",
+			sch: &scheduler.CreateScheduleInput{
+				ScheduleExpression: aws.String("rate(1 day)"),
+				StartDate:          date("2024-02-10T01:00:00Z"),
+			},
+			anchor: date("2024-01-30T00:10:00Z"),
+			n:      2,
+			want:   []string{"2024-02-10T01:00:00Z", "2024-02-11T01:00:00Z"},
+		},
+		{
+			name: "at",
+			sch: &scheduler.CreateScheduleInput{
+				ScheduleExpression:         aws.String("at(2024-02-01T10:00:00)"),
+				ScheduleExpressionTimezone: aws.String("Asia/Tokyo"),
+				// Ignored for one-time schedule.
+				EndDate: date("2024-01-31T00:00:00Z"),
+			},
+			n:    10,
+			want: []string{"2024-02-01T10:00:00+09:00"},
+		},
+		{
+			name: "at past",
+			sch:  &scheduler.CreateScheduleInput{ScheduleExpression: aws.String("at(2024-01-01T10:00:00)")},
+			n:    10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := nextFireTimes(tt.sch, tt.anchor, now, tt.n)
+			assert.NoError(err)
+			var s []string
+			for _, t := range got {
+				s = append(s, t.Format(time.RFC3339))
+			}
+			assert.Equal(tt.want, s)
+		})
+	}
+
+	t.Run("err timezone", func(t *testing.T) {
+		assert := assert.New(t)
+
+		_, err := nextFireTimes(&scheduler.CreateScheduleInput{
+			ScheduleExpression:         aws.String("rate(1 day)"),
+			ScheduleExpressionTimezone: aws.String("Asia/Nowhere"),
+		}, nil, now, 1)
+		assert.EqualError(err, `ScheduleExpressionTimezone: unknown time zone Asia/Nowhere`)
+	})
+}
+
+func Test_next(t *testing.T) {
+	defer func(f func() time.Time) { timeNow = f }(timeNow)
+	timeNow = func() time.Time { return time.Date(2024, 1, 30, 12, 34, 56, 0, time.UTC) }
+
+	t.Run("local", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"next", "--schedule", "testdata/update/normal.yml", "-n", "3"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+		assert.Equal(`# some-group/some-schedule cron(*/3 * * * ? *) Asia/Tokyo
+LOCAL                      WEEKDAY  UTC
+2024-01-30T21:36:00+09:00  Tue      2024-01-30T12:36:00Z
+2024-01-30T21:39:00+09:00  Tue      2024-01-30T12:39:00Z
+2024-01-30T21:42:00+09:00  Tue      2024-01-30T12:42:00Z
+`, out.String())
+	})
+
+	t.Run("remote", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("daily"),
+			GroupName: aws.String("multi-group"),
+		}).Return(remoteDailyForTest(), nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"next", "--name", "daily", "--group", "multi-group", "-n", "2"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+		assert.Equal(`# multi-group/daily cron(0 3 * * ? *) UTC
+LOCAL                 WEEKDAY  UTC
+2024-01-31T03:00:00Z  Wed      2024-01-31T03:00:00Z
+2024-02-01T03:00:00Z  Thu      2024-02-01T03:00:00Z
+`, out.String())
+	})
+}
@@ -0,0 +1,128 @@
+package ebschedule
+
+import (
+	"fmt"
+	"time"
+)
+
+// scheduleDefaults are the values which EventBridge Scheduler applies when they are omitted.
+// Each default is applied only when Parent exists.
+var scheduleDefaults = []struct {
+	Parent string
+	Path   string
+	Value  any
+}{
+	{Parent: "", Path: "/ActionAfterCompletion", Value: "NONE"},
+	{Parent: "", Path: "/GroupName", Value: "default"},
+	{Parent: "", Path: "/ScheduleExpressionTimezone", Value: "UTC"},
+	{Parent: "", Path: "/State", Value: "ENABLED"},
+	{Parent: "/Target", Path: "/Target/RetryPolicy/MaximumEventAgeInSeconds", Value: int64(86400)},
+	{Parent: "/Target", Path: "/Target/RetryPolicy/MaximumRetryAttempts", Value: int64(185)},
+	{Parent: "/Target/EcsParameters", Path: "/Target/EcsParameters/TaskCount", Value: int64(1)},
+}
+
+// normalizeScheduleDocument makes the documents made by scheduleDocument comparable semantically.
+// It drops null and empty values, applies the defaults of the service and formats dates in UTC.
+func normalizeScheduleDocument(doc any) (any, error) {
+	doc, err := dropEmptyExceptInput(doc, dropEmptyValues)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, nil
+	}
+
+	for _, d := range scheduleDefaults {
+		if d.Parent != "" {
+			if _, err := lookupPointer(doc, d.Parent); err != nil {
+				continue
+			}
+		}
+		cur, err := lookupPointer(doc, d.Path)
+		if err == nil && cur != "" {
+			continue
+		}
+		doc, err = setPointerWithParents(doc, d.Path, d.Value)
+		if err != nil {
+			return nil, fmt.Errorf("setPointerWithParents(%s): %w", d.Path, err)
+		}
+	}
+
+	// MaximumWindowInMinutes is meaningless when the mode is OFF.
+	if mode, err := lookupPointer(doc, "/FlexibleTimeWindow/Mode"); err == nil && mode == "OFF" {
+		var err error
+		doc, _, err = removeValue(doc, "/FlexibleTimeWindow/MaximumWindowInMinutes")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, p := range []string{"/StartDate", "/EndDate"} {
+		v, err := lookupPointer(doc, p)
+		if err != nil {
+			continue
+		}
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			continue
+		}
+		if err := setValue(doc, p, t.UTC().Format(time.RFC3339)); err != nil {
+			return nil, fmt.Errorf("setValue(%s): %w", p, err)
+		}
+	}
+
+	return doc, nil
+}
+
+// dropEmptyExceptInput applies drop to the document except Target.Input, whose empty values are the data sent to the target.
+// Input is detached while dropping, because drop modifies the values in place.
+func dropEmptyExceptInput(doc any, drop func(any) any) (any, error) {
+	input, inputErr := lookupPointer(doc, targetInputPath)
+	if inputErr == nil {
+		var err error
+		doc, _, err = removeValue(doc, targetInputPath)
+		if err != nil {
+			return nil, fmt.Errorf("removeValue(%s): %w", targetInputPath, err)
+		}
+	}
+
+	doc = drop(doc)
+	if inputErr != nil || input == nil {
+		return doc, nil
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	doc, err := setPointerWithParents(doc, targetInputPath, input)
+	if err != nil {
+		return nil, fmt.Errorf("setPointerWithParents(%s): %w", targetInputPath, err)
+	}
+	return doc, nil
+}
+
+// dropEmptyValues removes null, empty objects and empty arrays recursively.
+// It returns nil if v itself becomes empty.
+func dropEmptyValues(v any) any {
+	switch vv := v.(type) {
+	case map[string]any:
+		for k, e := range vv {
+			if d := dropEmptyValues(e); d == nil {
+				delete(vv, k)
+			} else {
+				vv[k] = d
+			}
+		}
+		if len(vv) == 0 {
+			return nil
+		}
+	case []any:
+		if len(vv) == 0 {
+			return nil
+		}
+	}
+	return v
+}
@@ -0,0 +1,196 @@
+package ebschedule
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/stretchr/testify/assert"
+	mock_ebschedule "github.com/tckz/ebschedule/mock"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_normalizeScheduleDocument(t *testing.T) {
+	local := func() *scheduler.CreateScheduleInput {
+		return &scheduler.CreateScheduleInput{
+			Name:      aws.String("daily"),
+			GroupName: aws.String("multi-group"),
+			FlexibleTimeWindow: &types.FlexibleTimeWindow{
+				Mode: types.FlexibleTimeWindowModeOff,
+			},
+			ScheduleExpression: aws.String("cron(0 3 * * ? *)"),
+			Target: &types.Target{
+				Arn:     aws.String("arn:aws:lambda:ap-northeast-1:99999:function:some-func"),
+				RoleArn: aws.String("arn:aws:iam::99999:role/some-scheduler-role"),
+			},
+		}
+	}
+	remote := func() *scheduler.GetScheduleOutput {
+		ret := remoteDailyForTest()
+		ret.ActionAfterCompletion = types.ActionAfterCompletionNone
+		ret.ScheduleExpressionTimezone = aws.String("UTC")
+		ret.Target.RetryPolicy = &types.RetryPolicy{
+			MaximumEventAgeInSeconds: aws.Int32(86400),
+			MaximumRetryAttempts:     aws.Int32(185),
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name   string
+		local  func(*scheduler.CreateScheduleInput)
+		remote func(*scheduler.GetScheduleOutput)
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name: "partial-retry-policy",
+			local: func(in *scheduler.CreateScheduleInput) {
+				in.Target.RetryPolicy = &types.RetryPolicy{MaximumRetryAttempts: aws.Int32(185)}
+			},
+		},
+		{
+			name: "flexible-time-window-off",
+			local: func(in *scheduler.CreateScheduleInput) {
+				in.FlexibleTimeWindow.MaximumWindowInMinutes = aws.Int32(0)
+			},
+		},
+		{
+			name: "empty-string",
+			local: func(in *scheduler.CreateScheduleInput) {
+				in.ScheduleExpressionTimezone = aws.String("")
+			},
+		},
+		{
+			name: "dates",
+			local: func(in *scheduler.CreateScheduleInput) {
+				in.StartDate = aws.Time(time.Date(2024, 1, 2, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60)))
+			},
+			remote: func(out *scheduler.GetScheduleOutput) {
+				out.StartDate = aws.Time(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+			},
+		},
+		{
+			name: "ecs-task-count",
+			local: func(in *scheduler.CreateScheduleInput) {
+				in.Target.EcsParameters = &types.EcsParameters{
+					TaskDefinitionArn: aws.String("arn:aws:ecs:ap-northeast-1:99999:task-definition/some-task"),
+				}
+			},
+			remote: func(out *scheduler.GetScheduleOutput) {
+				out.Target.EcsParameters = &types.EcsParameters{
+					TaskDefinitionArn: aws.String("arn:aws:ecs:ap-northeast-1:99999:task-definition/some-task"),
+					TaskCount:         aws.Int32(1),
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			l, r := local(), remote()
+			if tt.local != nil {
+				tt.local(l)
+			}
+			if tt.remote != nil {
+				tt.remote(r)
+			}
+
+			from, err := documentForDiff(r, nil)
+			assert.NoError(err)
+			to, err := documentForDiff(l, nil)
+			assert.NoError(err)
+			assert.Empty(computeJSONPatch(from, to))
+		})
+	}
+
+	t.Run("changed", func(t *testing.T) {
+		assert := assert.New(t)
+
+		l := local()
+		l.Target.RetryPolicy = &types.RetryPolicy{MaximumRetryAttempts: aws.Int32(0)}
+
+		from, err := documentForDiff(remote(), nil)
+		assert.NoError(err)
+		to, err := documentForDiff(l, nil)
+		assert.NoError(err)
+		assert.Equal([]jsonPatchOperation{
+			{Op: "replace", Path: "/Target/RetryPolicy/MaximumRetryAttempts", Value: int64(0)},
+		}, computeJSONPatch(from, to))
+	})
+}
+
+func Test_normalizeScheduleDocument_input(t *testing.T) {
+	// Empty values in Target.Input are the data sent to the target, which are not dropped.
+	for _, tt := range [][2]string{
+		{`{"a":null}`, `{}`},
+		{`{"list":[]}`, `{}`},
+		{`{"o":{}}`, `{}`},
+		{`[]`, `{}`},
+	} {
+		t.Run(tt[0], func(t *testing.T) {
+			assert := assert.New(t)
+
+			l := &scheduler.CreateScheduleInput{Target: &types.Target{Input: aws.String(tt[0])}}
+			r := &scheduler.GetScheduleOutput{Target: &types.Target{Input: aws.String(tt[1])}}
+			from, err := documentForDiff(r, nil)
+			assert.NoError(err)
+			to, err := documentForDiff(l, nil)
+			assert.NoError(err)
+			assert.NotEmpty(computeJSONPatch(from, to))
+		})
+	}
+}
+
+func Test_dropEmptyValues(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(map[string]any{
+		"A": "a",
+		"D": []any{nil},
+	}, dropEmptyValues(map[string]any{
+		"A": "a",
+		"B": nil,
+		"C": map[string]any{"X": nil, "Y": []any{}},
+		"D": []any{nil},
+	}))
+	assert.Nil(dropEmptyValues(map[string]any{"B": nil}))
+}
+
+func Test_diffAWSDefaults(t *testing.T) {
+	assert := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	out := bytes.NewBuffer(nil)
+	cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+	// The remote schedule is filled with the defaults which testdata/multi/daily.yaml omits.
+	remote := remoteDailyForTest()
+	remote.ActionAfterCompletion = types.ActionAfterCompletionNone
+	remote.ScheduleExpressionTimezone = aws.String("UTC")
+	remote.Target.RetryPolicy = &types.RetryPolicy{
+		MaximumEventAgeInSeconds: aws.Int32(86400),
+		MaximumRetryAttempts:     aws.Int32(185),
+	}
+	cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(remote, nil)
+
+	cmd := NewCommand(&CommandInput{
+		AppName:         "ut",
+		Version:         "v0.0.1",
+		SchedulerClient: cl,
+		OutWriter:       out,
+	})
+	cmd.SetArgs([]string{"diff", "--schedule", "testdata/multi/daily.yaml", "--exit-code"})
+	err := cmd.ExecuteContext(context.Background())
+
+	assert.NoError(err)
+	assert.Equal(``, out.String())
+}
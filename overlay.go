@@ -0,0 +1,348 @@
+package ebschedule
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/kayac/go-config"
+)
+
+// overlaySuffix is the suffix of overlay files, which are skipped when schedule files are searched in directories.
+const overlaySuffix = ".overlay"
+
+// overlay is a patch applied to schedule.yaml.
+// A mapping is RFC 7386 JSON Merge Patch, and a sequence is RFC 6902 JSON Patch.
+type overlay struct {
+	FileName   string
+	MergePatch map[string]any
+	JSONPatch  []overlayOperation
+}
+
+type overlayOperation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+func isOverlayFile(fn string) bool {
+	return strings.HasSuffix(strings.TrimSuffix(fn, filepath.Ext(fn)), overlaySuffix)
+}
+
+// envOverlayFile returns path/to/schedule.<env>.overlay.yml for path/to/schedule.yml if it exists.
+func envOverlayFile(fn, env string) (string, bool) {
+	base := strings.TrimSuffix(fn, filepath.Ext(fn))
+	for _, ext := range []string{".yml", ".yaml"} {
+		p := base + "." + env + overlaySuffix + ext
+		if _, err := os.Stat(p); err == nil {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// readOverlays reads overlays for the schedule file fn, the one selected by Env first and then Overlays.
+func readOverlays(fn string, opts loadOptions) ([]*overlay, error) {
+	var files []string
+	if opts.Env != "" {
+		if p, ok := envOverlayFile(fn, opts.Env); ok {
+			files = append(files, p)
+		}
+	}
+	files = append(files, opts.Overlays...)
+
+	var ret []*overlay
+	for _, p := range files {
+		o, err := readOverlay(p, opts.Strict)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		ret = append(ret, o)
+	}
+	return ret, nil
+}
+
+func readOverlay(fn string, strict bool) (*overlay, error) {
+	b, err := config.ReadWithEnv(fn)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := parseYAMLDocuments(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) != 1 {
+		return nil, errors.New("overlay must be a single document")
+	}
+	js, err := documentToJSON(docs[0].Body)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &overlay{FileName: fn}
+	switch t := bytes.TrimSpace(js); {
+	case len(t) > 0 && t[0] == '{':
+		if strict {
+			if err := validateScheduleFields(docs[0].Body); err != nil {
+				return nil, fmt.Errorf("validateScheduleFields: %w", err)
+			}
+		}
+		v, err := decodeJSON(js)
+		if err != nil {
+			return nil, err
+		}
+		o.MergePatch = v.(map[string]any)
+	case len(t) > 0 && t[0] == '[':
+		if err := json.Unmarshal(js, &o.JSONPatch); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+	default:
+		return nil, errors.New("overlay must be a mapping(JSON Merge Patch) or a sequence(JSON Patch)")
+	}
+	return o, nil
+}
+
+// applyOverlays applies overlays to the JSON converted from schedule.yaml.
+func applyOverlays(js []byte, overlays []*overlay) ([]byte, error) {
+	if len(overlays) == 0 {
+		return js, nil
+	}
+
+	doc, err := decodeJSON(js)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range overlays {
+		doc, err = o.apply(doc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", o.FileName, err)
+		}
+	}
+	return json.Marshal(doc)
+}
+
+func (o *overlay) apply(doc any) (any, error) {
+	if o.MergePatch != nil {
+		return mergePatch(doc, o.MergePatch), nil
+	}
+
+	for i, op := range o.JSONPatch {
+		var err error
+		doc, err = applyOperation(doc, op)
+		if err != nil {
+			return nil, fmt.Errorf("[%d] %s: %w", i, op.Op, err)
+		}
+	}
+	return doc, nil
+}
+
+// mergePatch applies RFC 7386 JSON Merge Patch.
+// Keys are matched case-insensitively as encoding/json does, so that a patch does not add a sibling of existing key.
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+
+	for k, v := range p {
+		key := k
+		if _, ok := t[k]; !ok {
+			for tk := range t {
+				if strings.EqualFold(tk, k) {
+					key = tk
+					break
+				}
+			}
+		}
+		if v == nil {
+			delete(t, key)
+			continue
+		}
+		t[key] = mergePatch(t[key], v)
+	}
+	return t
+}
+
+func applyOperation(doc any, op overlayOperation) (any, error) {
+	if op.Path == nil {
+		return nil, errors.New("path must be specified")
+	}
+	path := *op.Path
+
+	value := func() (any, error) {
+		if op.Value == nil {
+			return nil, errors.New("value must be specified")
+		}
+		return decodeJSON(op.Value)
+	}
+	from := func() (string, any, error) {
+		if op.From == nil {
+			return "", nil, errors.New("from must be specified")
+		}
+		v, err := lookupPointer(doc, *op.From)
+		return *op.From, v, err
+	}
+
+	switch op.Op {
+	case "add":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		return addPointer(doc, path, v)
+	case "remove":
+		return removePointer(doc, path)
+	case "replace":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := lookupPointer(doc, path); err != nil {
+			return nil, err
+		}
+		if path == "" {
+			return v, nil
+		}
+		return doc, setValue(doc, path, v)
+	case "move":
+		f, v, err := from()
+		if err != nil {
+			return nil, err
+		}
+		doc, err = removePointer(doc, f)
+		if err != nil {
+			return nil, err
+		}
+		return addPointer(doc, path, v)
+	case "copy":
+		_, v, err := from()
+		if err != nil {
+			return nil, err
+		}
+		// Copy not to share the value between paths.
+		v, err = deepCopyJSON(v)
+		if err != nil {
+			return nil, err
+		}
+		return addPointer(doc, path, v)
+	case "test":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		cur, err := lookupPointer(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(cur, v) {
+			return nil, fmt.Errorf("value of %s is not the expected one", path)
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown op: %q", op.Op)
+}
+
+func lookupPointer(doc any, path string) (any, error) {
+	if path == "" {
+		return doc, nil
+	}
+	var v *any
+	found, err := getValue(doc, path, &v)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%s does not exist", path)
+	}
+	if v == nil {
+		return nil, nil
+	}
+	return *v, nil
+}
+
+func removePointer(doc any, path string) (any, error) {
+	if path == "" {
+		return nil, errors.New("root cannot be removed")
+	}
+	ret, removed, err := removeValue(doc, path)
+	if err != nil {
+		return nil, err
+	}
+	if !removed {
+		return nil, fmt.Errorf("%s does not exist", path)
+	}
+	return ret, nil
+}
+
+// addPointer adds v at path. An element is inserted when the parent is an array, where "-" means the end of the array.
+func addPointer(doc any, path string, v any) (any, error) {
+	if path == "" {
+		return v, nil
+	}
+
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid JSON pointer: %q", path)
+	}
+	parentPath, token := path[:i], path[i+1:]
+	parent, err := lookupPointer(doc, parentPath)
+	if err != nil {
+		return nil, err
+	}
+
+	switch p := parent.(type) {
+	case map[string]any:
+		p[unescapeJSONPointer(token)] = v
+		return doc, nil
+	case []any:
+		n := len(p)
+		if token != "-" {
+			n, err = strconv.Atoi(token)
+			if err != nil || n < 0 || n > len(p) {
+				return nil, fmt.Errorf("invalid index of %s: %q", parentPath, token)
+			}
+		}
+		arr := make([]any, 0, len(p)+1)
+		arr = append(arr, p[:n]...)
+		arr = append(arr, v)
+		arr = append(arr, p[n:]...)
+		if parentPath == "" {
+			return arr, nil
+		}
+		return doc, setValue(doc, parentPath, arr)
+	}
+	return nil, fmt.Errorf("%s is neither an object nor an array", parentPath)
+}
+
+func unescapeJSONPointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+}
+
+func decodeJSON(js []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("json.Decode: %w", err)
+	}
+	return v, nil
+}
+
+func deepCopyJSON(v any) (any, error) {
+	js, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(js)
+}
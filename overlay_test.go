@@ -0,0 +1,218 @@
+package ebschedule
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_prepareInputSchedule_overlay(t *testing.T) {
+	base := func() *scheduler.CreateScheduleInput {
+		return &scheduler.CreateScheduleInput{
+			FlexibleTimeWindow: &types.FlexibleTimeWindow{Mode: types.FlexibleTimeWindowModeOff},
+			GroupName:          aws.String("overlay-group"),
+			Name:               aws.String("hello"),
+			ScheduleExpression: aws.String("cron(0 3 * * ? *)"),
+			State:              types.ScheduleStateDisabled,
+			Target: &types.Target{
+				Arn:     aws.String("arn:aws:ecs:ap-northeast-1:99999:cluster/dev"),
+				RoleArn: aws.String("arn:aws:iam::99999:role/some-scheduler-role"),
+				EcsParameters: &types.EcsParameters{
+					TaskDefinitionArn: aws.String("arn:aws:ecs:ap-northeast-1:99999:task-definition/hello"),
+					NetworkConfiguration: &types.NetworkConfiguration{
+						AwsvpcConfiguration: &types.AwsVpcConfiguration{
+							Subnets: []string{"subnet-dev"},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		opts    loadOptions
+		want    func() *scheduler.CreateScheduleInput
+		wantErr string
+	}{
+		{
+			name: "no overlay",
+			opts: loadOptions{Strict: true, Env: "staging"},
+			want: base,
+		},
+		{
+			name: "env",
+			opts: loadOptions{Strict: true, Env: "prod"},
+			want: func() *scheduler.CreateScheduleInput {
+				sch := base()
+				sch.State = types.ScheduleStateEnabled
+				sch.Target.Arn = aws.String("arn:aws:ecs:ap-northeast-1:99999:cluster/prod")
+				sch.Target.EcsParameters.NetworkConfiguration.AwsvpcConfiguration.Subnets = []string{"subnet-prod-a", "subnet-prod-c"}
+				return sch
+			},
+		},
+		{
+			name: "env and overlay",
+			opts: loadOptions{Strict: true, Env: "prod", Overlays: []string{"testdata/overlay/extra-subnet.overlay.yml"}},
+			want: func() *scheduler.CreateScheduleInput {
+				sch := base()
+				sch.ScheduleExpression = aws.String("cron(0 4 * * ? *)")
+				sch.State = types.ScheduleStateEnabled
+				sch.Target.Arn = aws.String("arn:aws:ecs:ap-northeast-1:99999:cluster/prod")
+				sch.Target.EcsParameters.NetworkConfiguration.AwsvpcConfiguration.Subnets = []string{"subnet-prod-a", "subnet-prod-c", "subnet-extra"}
+				return sch
+			},
+		},
+		{
+			name:    "err-strict",
+			opts:    loadOptions{Strict: true, Overlays: []string{"testdata/overlay/err-unknown.overlay.yml"}},
+			wantErr: `readOverlays: testdata/overlay/err-unknown.overlay.yml: validateScheduleFields: [2:3] unknown field "RetryPolcy" in Target`,
+		},
+		{
+			name: "err-strict-json-patch",
+			opts: loadOptions{Strict: true, Overlays: []string{"testdata/overlay/err-patch.overlay.yml"}},
+			wantErr: `validateMergedSchedule: unknown field "RetryPolcy" in Target
+duplicated field "roleArn" in Target, already specified as "RoleArn"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := prepareInputSchedule("testdata/overlay/hello.yml", tt.opts)
+			if tt.wantErr != "" {
+				assert.EqualError(err, tt.wantErr)
+				return
+			}
+			if assert.NoError(err) {
+				if assert.Len(got, 1) {
+					assert.Equal(tt.want(), got[0].Input)
+				}
+			}
+		})
+	}
+
+	t.Run("dir skips overlays", func(t *testing.T) {
+		assert := assert.New(t)
+
+		schs, err := prepareInputSchedules([]string{"testdata/overlay"}, loadOptions{Strict: true, Env: "prod"})
+		assert.NoError(err)
+		if assert.Len(schs, 1) {
+			assert.Equal("testdata/overlay/hello.yml", schs[0].FileName)
+			assert.Equal(types.ScheduleStateEnabled, schs[0].Input.State)
+		}
+	})
+}
+
+func Test_applyOverlays(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   []overlayOperation
+		want    string
+		wantErr string
+	}{
+		{
+			name: "add",
+			patch: []overlayOperation{
+				{Op: "add", Path: aws.String("/a/b"), Value: []byte(`{"c":1}`)},
+				{Op: "add", Path: aws.String("/list/0"), Value: []byte(`"first"`)},
+				{Op: "add", Path: aws.String("/list/-"), Value: []byte(`"last"`)},
+			},
+			want: `{"a":{"b":{"c":1},"x":"y"},"list":["first",1,2,"last"]}`,
+		},
+		{
+			name: "remove",
+			patch: []overlayOperation{
+				{Op: "remove", Path: aws.String("/a/x")},
+				{Op: "remove", Path: aws.String("/list/0")},
+			},
+			want: `{"a":{},"list":[2]}`,
+		},
+		{
+			name: "replace",
+			patch: []overlayOperation{
+				{Op: "replace", Path: aws.String("/a/x"), Value: []byte(`"z"`)},
+				{Op: "replace", Path: aws.String("/list/1"), Value: []byte(`3`)},
+			},
+			want: `{"a":{"x":"z"},"list":[1,3]}`,
+		},
+		{
+			name: "move and copy",
+			patch: []overlayOperation{
+				{Op: "copy", From: aws.String("/list"), Path: aws.String("/copied")},
+				{Op: "move", From: aws.String("/a/x"), Path: aws.String("/moved")},
+				{Op: "add", Path: aws.String("/copied/-"), Value: []byte(`9`)},
+			},
+			want: `{"a":{},"copied":[1,2,9],"list":[1,2],"moved":"y"}`,
+		},
+		{
+			name: "test",
+			patch: []overlayOperation{
+				{Op: "test", Path: aws.String("/a/x"), Value: []byte(`"y"`)},
+			},
+			want: `{"a":{"x":"y"},"list":[1,2]}`,
+		},
+		{
+			name: "err-test",
+			patch: []overlayOperation{
+				{Op: "test", Path: aws.String("/a/x"), Value: []byte(`"z"`)},
+			},
+			wantErr: `overlay.yml: [0] test: value of /a/x is not the expected one`,
+		},
+		{
+			name: "err-replace",
+			patch: []overlayOperation{
+				{Op: "add", Path: aws.String("/a/b"), Value: []byte(`1`)},
+				{Op: "replace", Path: aws.String("/a/c"), Value: []byte(`1`)},
+			},
+			wantErr: `overlay.yml: [1] replace: /a/c does not exist`,
+		},
+		{
+			name: "err-index",
+			patch: []overlayOperation{
+				{Op: "add", Path: aws.String("/list/3"), Value: []byte(`1`)},
+			},
+			wantErr: `overlay.yml: [0] add: invalid index of /list: "3"`,
+		},
+		{
+			name: "err-op",
+			patch: []overlayOperation{
+				{Op: "merge", Path: aws.String("/a")},
+			},
+			wantErr: `overlay.yml: [0] merge: unknown op: "merge"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := applyOverlays([]byte(`{"a":{"x":"y"},"list":[1,2]}`), []*overlay{
+				{FileName: "overlay.yml", JSONPatch: tt.patch},
+			})
+			if tt.wantErr != "" {
+				assert.EqualError(err, tt.wantErr)
+				return
+			}
+			if assert.NoError(err) {
+				assert.JSONEq(tt.want, string(got))
+			}
+		})
+	}
+
+	t.Run("merge patch", func(t *testing.T) {
+		assert := assert.New(t)
+
+		got, err := applyOverlays([]byte(`{"a":{"x":"y","z":1},"list":[1,2]}`), []*overlay{
+			{FileName: "overlay.yml", MergePatch: map[string]any{
+				"A":    map[string]any{"x": nil, "w": "v"},
+				"list": []any{"replaced"},
+				"new":  true,
+			}},
+		})
+		assert.NoError(err)
+		assert.JSONEq(`{"a":{"z":1,"w":"v"},"list":["replaced"],"new":true}`, string(got))
+	})
+}
@@ -0,0 +1,37 @@
+package ebschedule
+
+import (
+	"context"
+	"sync"
+)
+
+type parallelResult[R any] struct {
+	Value R
+	Err   error
+}
+
+// runParallel calls f for each item with at most concurrency goroutines.
+// Results are returned in the same order as items regardless of the order of completion.
+func runParallel[T, R any](ctx context.Context, concurrency int, items []T, f func(ctx context.Context, item T) (R, error)) []parallelResult[R] {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]parallelResult[R], len(items))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, item := range items {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			v, err := f(ctx, item)
+			results[i] = parallelResult[R]{Value: v, Err: err}
+		}()
+	}
+	wg.Wait()
+	return results
+}
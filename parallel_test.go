@@ -0,0 +1,53 @@
+package ebschedule
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_runParallel(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		assert := assert.New(t)
+
+		var running, maxRunning atomic.Int32
+		items := []int{5, 4, 3, 2, 1}
+		results := runParallel(context.Background(), 3, items, func(ctx context.Context, item int) (int, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+
+			// Later items finish earlier.
+			time.Sleep(time.Duration(item) * 5 * time.Millisecond)
+			if item == 3 {
+				return 0, errors.New("err@3")
+			}
+			return item * 10, nil
+		})
+
+		assert.Equal([]parallelResult[int]{
+			{Value: 50},
+			{Value: 40},
+			{Value: 0, Err: errors.New("err@3")},
+			{Value: 20},
+			{Value: 10},
+		}, results)
+		assert.LessOrEqual(maxRunning.Load(), int32(3))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		results := runParallel(context.Background(), 0, []int{}, func(ctx context.Context, item int) (int, error) {
+			return item, nil
+		})
+		assert.Empty(t, results)
+	})
+}
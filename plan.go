@@ -0,0 +1,263 @@
+package ebschedule
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+const planFormatVersion = 1
+
+// schedulePlan is the content of plan file which is made by plan and consumed by apply.
+type schedulePlan struct {
+	Version   int
+	CreatedAt time.Time
+	Schedules []*plannedSchedule
+}
+
+type plannedSchedule struct {
+	File  string
+	Input *scheduler.CreateScheduleInput
+	// Patch is RFC 6902 JSON Patch from the remote schedule to Input. Empty if there is no change.
+	Patch []jsonPatchOperation
+	// Remote is the normalized remote schedule observed at planning. nil if it did not exist.
+	Remote any
+	// RemoteLastModificationDate is LastModificationDate of the remote schedule observed at planning.
+	RemoteLastModificationDate *time.Time
+	// RemoteHash is the hash of Remote. Empty if the remote schedule did not exist.
+	RemoteHash string
+	// IgnorePaths are the ones of the schedule at planning, which apply honors too.
+	IgnorePaths []string
+}
+
+func newPlanCommand(in *CommandInput) *cobra.Command {
+	return wrapCobra(&cobra.Command{
+		Use:   "plan",
+		Short: "Save the changes to apply later",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			patterns, _ := cmd.Flags().GetStringArray(OptSchedule)
+			optOut, _ := cmd.Flags().GetString(OptOut)
+			optConcurrency, _ := cmd.Flags().GetInt(OptConcurrency)
+
+			schs, err := prepareInputSchedules(patterns, loadOptionsFromFlags(cmd))
+			if err != nil {
+				return fmt.Errorf("prepareInputSchedules: %w", err)
+			}
+
+			p := &schedulePlan{
+				Version:   planFormatVersion,
+				CreatedAt: time.Now().UTC(),
+			}
+			type outcome struct {
+				ps      *plannedSchedule
+				unified string
+			}
+			results := runParallel(ctx, optConcurrency, schs, func(ctx context.Context, sch *inputSchedule) (outcome, error) {
+				ps, unified, err := planSchedule(ctx, in.SchedulerClient, sch)
+				return outcome{ps: ps, unified: unified}, err
+			})
+
+			var summary diffSummary
+			var errs []error
+			for i, sch := range schs {
+				ps, unified, err := results[i].Value.ps, results[i].Value.unified, results[i].Err
+				if err != nil {
+					summary.Failed++
+					errs = append(errs, fmt.Errorf("%s: %w", sch.ID(), err))
+					continue
+				}
+				if len(ps.Patch) == 0 {
+					summary.Unchanged++
+				} else {
+					summary.Changed++
+					fmt.Fprint(in.OutWriter, coloredDiff(unified))
+				}
+				p.Schedules = append(p.Schedules, ps)
+			}
+			log.Print(summary.String())
+			if len(errs) > 0 {
+				return errors.Join(errs...)
+			}
+
+			b, err := json.MarshalIndent(p, "", "  ")
+			if err != nil {
+				return fmt.Errorf("json.MarshalIndent: %w", err)
+			}
+			if err := os.WriteFile(optOut, b, 0644); err != nil {
+				return fmt.Errorf("os.WriteFile: %w", err)
+			}
+			log.Printf("Plan saved to %s", optOut)
+			return nil
+		},
+	}, func(cmd *cobra.Command) {
+		addScheduleFlag(cmd)
+		addConcurrencyFlag(cmd)
+		addIgnorePathFlag(cmd)
+		cmd.Flags().String(OptOut, "", "path/to/plan.json to write")
+		lo.Must0(cmd.MarkFlagRequired(OptOut))
+	})
+}
+
+func planSchedule(ctx context.Context, client SchedulerClient, sch *inputSchedule) (*plannedSchedule, string, error) {
+	diff, err := diffSchedule(ctx, client, sch)
+	if err != nil {
+		return nil, "", err
+	}
+	unified, err := diff.Unified()
+	if err != nil {
+		return nil, "", err
+	}
+	hash, err := documentHash(diff.From)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ps := &plannedSchedule{
+		File:        sch.FileName,
+		Input:       sch.Input,
+		Patch:       diff.JSONPatch(),
+		Remote:      diff.From,
+		RemoteHash:  hash,
+		IgnorePaths: sch.IgnorePaths,
+	}
+	if diff.Remote != nil {
+		ps.RemoteLastModificationDate = diff.Remote.LastModificationDate
+	}
+	return ps, unified, nil
+}
+
+func newApplyCommand(in *CommandInput) *cobra.Command {
+	return wrapCobra(&cobra.Command{
+		Use:   "apply path/to/plan.json",
+		Short: "Apply the changes saved by plan",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			optCreateScheduleGroup, _ := cmd.Flags().GetBool(OptCreateScheduleGroup)
+			optConcurrency, _ := cmd.Flags().GetInt(OptConcurrency)
+			optHistoryDir, _ := cmd.Flags().GetString(OptHistoryDir)
+
+			p, err := readPlan(args[0])
+			if err != nil {
+				return err
+			}
+
+			changed := lo.Filter(p.Schedules, func(e *plannedSchedule, _ int) bool { return len(e.Patch) > 0 })
+
+			// Verify all schedules before applying any of them.
+			var errs []error
+			for _, ps := range changed {
+				if err := verifyPlannedSchedule(ctx, in.SchedulerClient, ps); err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", scheduleID(ps.Input.GroupName, ps.Input.Name), err))
+				}
+			}
+			if len(errs) > 0 {
+				return errors.Join(errs...)
+			}
+
+			u := &updater{
+				client:              in.SchedulerClient,
+				out:                 in.OutWriter,
+				createScheduleGroup: optCreateScheduleGroup,
+				concurrency:         optConcurrency,
+				historyDir:          optHistoryDir,
+				checkedGroups:       map[string]error{},
+			}
+			summary, errs := u.updateAll(ctx, lo.Map(changed, func(e *plannedSchedule, _ int) *inputSchedule {
+				return &inputSchedule{FileName: e.File, Input: e.Input, IgnorePaths: e.IgnorePaths}
+			}))
+			log.Print(summary.String())
+
+			return errors.Join(errs...)
+		},
+	}, func(cmd *cobra.Command) {
+		addConcurrencyFlag(cmd)
+		addHistoryDirFlag(cmd)
+		cmd.Flags().Bool(OptCreateScheduleGroup, true, "create schedule group if not exist")
+	})
+}
+
+func readPlan(fn string) (*schedulePlan, error) {
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	var p schedulePlan
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	if p.Version != planFormatVersion {
+		return nil, fmt.Errorf("unsupported plan version: %d", p.Version)
+	}
+	return &p, nil
+}
+
+// verifyPlannedSchedule returns an error if the remote schedule has been changed since the plan was made.
+func verifyPlannedSchedule(ctx context.Context, client SchedulerClient, ps *plannedSchedule) error {
+	cur, err := client.GetSchedule(ctx, &scheduler.GetScheduleInput{
+		Name:      ps.Input.Name,
+		GroupName: ps.Input.GroupName,
+	})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if !errors.As(err, &notFound) {
+			return fmt.Errorf("scheduler.GetSchedule: %w", err)
+		}
+		cur = nil
+	}
+
+	if cur == nil {
+		if ps.Remote != nil {
+			return errors.New("remote schedule has been deleted since the plan was made")
+		}
+		return nil
+	}
+	if ps.Remote == nil {
+		return errors.New("remote schedule has been created since the plan was made")
+	}
+
+	if !lo.FromPtr(cur.LastModificationDate).Equal(lo.FromPtr(ps.RemoteLastModificationDate)) {
+		return fmt.Errorf("remote schedule has been modified since the plan was made: LastModificationDate=%s",
+			lo.FromPtr(cur.LastModificationDate).Format(time.RFC3339))
+	}
+
+	doc, err := documentForDiff(cur, ps.IgnorePaths)
+	if err != nil {
+		return fmt.Errorf("documentForDiff: %w", err)
+	}
+	hash, err := documentHash(doc)
+	if err != nil {
+		return err
+	}
+	if hash != ps.RemoteHash {
+		return errors.New("content of remote schedule has been changed since the plan was made")
+	}
+	return nil
+}
+
+// documentHash returns sha256 of the document. It returns empty string for nil.
+func documentHash(v any) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	// encoding/json sorts keys of map, so the result is stable.
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("json.Marshal: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,182 @@
+package ebschedule
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/stretchr/testify/assert"
+	mock_ebschedule "github.com/tckz/ebschedule/mock"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_planApply(t *testing.T) {
+	makePlan := func(t *testing.T, remote *scheduler.GetScheduleOutput) string {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("daily"),
+			GroupName: aws.String("multi-group"),
+		}).Return(remote, nil)
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("hourly"),
+			GroupName: aws.String("multi-group"),
+		}).Return(nil, &types.ResourceNotFoundException{})
+
+		fn := filepath.Join(t.TempDir(), "plan.json")
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       bytes.NewBuffer(nil),
+		})
+		cmd.SetArgs([]string{"plan", "--schedule", "testdata/multi", "--out", fn})
+		assert.NoError(t, cmd.ExecuteContext(context.Background()))
+		return fn
+	}
+
+	remoteForPlan := func() *scheduler.GetScheduleOutput {
+		remote := remoteDailyForTest()
+		remote.State = types.ScheduleStateDisabled
+		remote.LastModificationDate = aws.Time(time.Date(2023, 2, 3, 4, 5, 6, 0, time.UTC))
+		return remote
+	}
+
+	t.Run("apply", func(t *testing.T) {
+		assert := assert.New(t)
+
+		fn := makePlan(t, remoteForPlan())
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		// verification, then update
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("daily"),
+			GroupName: aws.String("multi-group"),
+		}).Return(remoteForPlan(), nil).Times(2)
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("hourly"),
+			GroupName: aws.String("multi-group"),
+		}).Return(nil, &types.ResourceNotFoundException{}).Times(2)
+		cl.EXPECT().GetScheduleGroup(gomock.Any(), gomock.Any()).
+			Return(&scheduler.GetScheduleGroupOutput{}, nil)
+		cl.EXPECT().UpdateSchedule(gomock.Any(), gomock.Any()).
+			Return(&scheduler.UpdateScheduleOutput{
+				ScheduleArn: aws.String("arn:aws:scheduler:ap-northeast-1:99999:schedule/multi-group/daily"),
+			}, nil)
+		cl.EXPECT().CreateSchedule(gomock.Any(), gomock.Any()).
+			Return(&scheduler.CreateScheduleOutput{
+				ScheduleArn: aws.String("arn:aws:scheduler:ap-northeast-1:99999:schedule/multi-group/hourly"),
+			}, nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		cmd.SetArgs([]string{"apply", fn, "--history-dir", t.TempDir()})
+		err := cmd.ExecuteContext(context.Background())
+
+		assert.NoError(err)
+		assert.Equal(`--- arn:aws:scheduler:ap-northeast-1:99999:schedule/multi-group/daily	2023-02-03T04:05:06Z
++++ testdata/multi/daily.yaml
+@@ -5,7 +5,7 @@
+ Name: daily
+ ScheduleExpression: cron(0 3 * * ? *)
+ ScheduleExpressionTimezone: UTC
+-State: DISABLED
++State: ENABLED
+ Target:
+   Arn: arn:aws:lambda:ap-northeast-1:99999:function:some-func
+   RetryPolicy:
+
+---
+ScheduleArn: arn:aws:scheduler:ap-northeast-1:99999:schedule/multi-group/daily
+ResultMetadata: {}
+---
+ScheduleArn: arn:aws:scheduler:ap-northeast-1:99999:schedule/multi-group/hourly
+ResultMetadata: {}
+`, out.String())
+	})
+
+	t.Run("refuse-modified", func(t *testing.T) {
+		assert := assert.New(t)
+
+		fn := makePlan(t, remoteForPlan())
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		modified := remoteForPlan()
+		modified.LastModificationDate = aws.Time(time.Date(2023, 2, 3, 4, 5, 7, 0, time.UTC))
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("daily"),
+			GroupName: aws.String("multi-group"),
+		}).Return(modified, nil)
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("hourly"),
+			GroupName: aws.String("multi-group"),
+		}).Return(remoteDailyForTest(), nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		cmd.SetArgs([]string{"apply", fn})
+		err := cmd.ExecuteContext(context.Background())
+
+		assert.EqualError(err, `multi-group/daily: remote schedule has been modified since the plan was made: LastModificationDate=2023-02-03T04:05:07Z
+multi-group/hourly: remote schedule has been created since the plan was made`)
+		assert.Equal(``, out.String())
+	})
+
+	t.Run("refuse-content-changed", func(t *testing.T) {
+		assert := assert.New(t)
+
+		fn := makePlan(t, remoteForPlan())
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		changed := remoteForPlan()
+		changed.ScheduleExpression = aws.String("cron(0 4 * * ? *)")
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("daily"),
+			GroupName: aws.String("multi-group"),
+		}).Return(changed, nil)
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("hourly"),
+			GroupName: aws.String("multi-group"),
+		}).Return(nil, &types.ResourceNotFoundException{})
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       bytes.NewBuffer(nil),
+		})
+		cmd.SetArgs([]string{"apply", fn})
+		err := cmd.ExecuteContext(context.Background())
+
+		assert.EqualError(err, `multi-group/daily: content of remote schedule has been changed since the plan was made`)
+	})
+}
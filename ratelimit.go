@@ -0,0 +1,73 @@
+package ebschedule
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"golang.org/x/time/rate"
+)
+
+var _ SchedulerClient = (*rateLimitedClient)(nil)
+
+// rateLimitedClient is a SchedulerClient which waits for the token bucket before each API call.
+type rateLimitedClient struct {
+	client  SchedulerClient
+	limiter *rate.Limiter
+}
+
+// newRateLimitedClient returns client which calls APIs at most rps requests per second.
+// It returns client as is when rps is not positive.
+func newRateLimitedClient(client SchedulerClient, rps float64) SchedulerClient {
+	if rps <= 0 {
+		return client
+	}
+	burst := max(int(rps), 1)
+	return &rateLimitedClient{
+		client:  client,
+		limiter: rate.NewLimiter(rate.Limit(rps), burst),
+	}
+}
+
+func callWithRateLimit[I, O any](ctx context.Context, limiter *rate.Limiter, f func(context.Context, I, ...func(*scheduler.Options)) (O, error), params I, optFns ...func(*scheduler.Options)) (O, error) {
+	if err := limiter.Wait(ctx); err != nil {
+		var zero O
+		return zero, err
+	}
+	return f(ctx, params, optFns...)
+}
+
+func (c *rateLimitedClient) GetScheduleGroup(ctx context.Context, params *scheduler.GetScheduleGroupInput, optFns ...func(*scheduler.Options)) (*scheduler.GetScheduleGroupOutput, error) {
+	return callWithRateLimit(ctx, c.limiter, c.client.GetScheduleGroup, params, optFns...)
+}
+
+func (c *rateLimitedClient) CreateScheduleGroup(ctx context.Context, params *scheduler.CreateScheduleGroupInput, optFns ...func(*scheduler.Options)) (*scheduler.CreateScheduleGroupOutput, error) {
+	return callWithRateLimit(ctx, c.limiter, c.client.CreateScheduleGroup, params, optFns...)
+}
+
+func (c *rateLimitedClient) DeleteScheduleGroup(ctx context.Context, params *scheduler.DeleteScheduleGroupInput, optFns ...func(*scheduler.Options)) (*scheduler.DeleteScheduleGroupOutput, error) {
+	return callWithRateLimit(ctx, c.limiter, c.client.DeleteScheduleGroup, params, optFns...)
+}
+
+func (c *rateLimitedClient) ListScheduleGroups(ctx context.Context, params *scheduler.ListScheduleGroupsInput, optFns ...func(*scheduler.Options)) (*scheduler.ListScheduleGroupsOutput, error) {
+	return callWithRateLimit(ctx, c.limiter, c.client.ListScheduleGroups, params, optFns...)
+}
+
+func (c *rateLimitedClient) GetSchedule(ctx context.Context, params *scheduler.GetScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.GetScheduleOutput, error) {
+	return callWithRateLimit(ctx, c.limiter, c.client.GetSchedule, params, optFns...)
+}
+
+func (c *rateLimitedClient) CreateSchedule(ctx context.Context, params *scheduler.CreateScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.CreateScheduleOutput, error) {
+	return callWithRateLimit(ctx, c.limiter, c.client.CreateSchedule, params, optFns...)
+}
+
+func (c *rateLimitedClient) UpdateSchedule(ctx context.Context, params *scheduler.UpdateScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.UpdateScheduleOutput, error) {
+	return callWithRateLimit(ctx, c.limiter, c.client.UpdateSchedule, params, optFns...)
+}
+
+func (c *rateLimitedClient) DeleteSchedule(ctx context.Context, params *scheduler.DeleteScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.DeleteScheduleOutput, error) {
+	return callWithRateLimit(ctx, c.limiter, c.client.DeleteSchedule, params, optFns...)
+}
+
+func (c *rateLimitedClient) ListSchedules(ctx context.Context, params *scheduler.ListSchedulesInput, optFns ...func(*scheduler.Options)) (*scheduler.ListSchedulesOutput, error) {
+	return callWithRateLimit(ctx, c.limiter, c.client.ListSchedules, params, optFns...)
+}
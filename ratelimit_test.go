@@ -0,0 +1,95 @@
+package ebschedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/stretchr/testify/assert"
+	mock_ebschedule "github.com/tckz/ebschedule/mock"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/time/rate"
+)
+
+func Test_rateLimitedClient(t *testing.T) {
+	params := &scheduler.GetScheduleInput{
+		Name:      aws.String("some-schedule"),
+		GroupName: aws.String("some-group"),
+	}
+
+	t.Run("spaced", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+		cl.EXPECT().GetSchedule(gomock.Any(), params).Return(&scheduler.GetScheduleOutput{}, nil).Times(4)
+
+		c := &rateLimitedClient{
+			client:  cl,
+			limiter: rate.NewLimiter(rate.Every(20*time.Millisecond), 1),
+		}
+		start := time.Now()
+		for range 4 {
+			_, err := c.GetSchedule(context.Background(), params)
+			assert.NoError(err)
+		}
+		// The first call consumes the burst, and the rest wait for 20ms each.
+		assert.GreaterOrEqual(time.Since(start), 60*time.Millisecond)
+	})
+
+	t.Run("burst", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+		if c, ok := newRateLimitedClient(cl, 10).(*rateLimitedClient); assert.True(ok) {
+			assert.Equal(rate.Limit(10), c.limiter.Limit())
+			assert.Equal(10, c.limiter.Burst())
+		}
+		if c, ok := newRateLimitedClient(cl, 0.5).(*rateLimitedClient); assert.True(ok) {
+			assert.Equal(1, c.limiter.Burst())
+		}
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+		assert.Same(cl, newRateLimitedClient(cl, 0))
+		assert.Same(cl, newRateLimitedClient(cl, -1))
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+		cl.EXPECT().GetSchedule(gomock.Any(), params).Return(&scheduler.GetScheduleOutput{}, nil).Times(1)
+
+		c := &rateLimitedClient{
+			client:  cl,
+			limiter: rate.NewLimiter(rate.Every(time.Hour), 1),
+		}
+		_, err := c.GetSchedule(context.Background(), params)
+		assert.NoError(err)
+
+		// The second call is aborted while waiting for the token without calling the API.
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		start := time.Now()
+		_, err = c.GetSchedule(ctx, params)
+		assert.ErrorIs(err, context.Canceled)
+		assert.Less(time.Since(start), time.Second)
+	})
+}
@@ -0,0 +1,116 @@
+package ebschedule
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+)
+
+var _ SchedulerClient = (*retryClient)(nil)
+
+// retryClient is a SchedulerClient which retries API calls on throttling and transient errors
+// with exponential backoff and full jitter.
+type retryClient struct {
+	client      SchedulerClient
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+// newRetryClient returns client which calls each API at most maxAttempts times.
+// It returns client as is when maxAttempts is less than 2.
+func newRetryClient(client SchedulerClient, maxAttempts int) SchedulerClient {
+	if maxAttempts < 2 {
+		return client
+	}
+	return &retryClient{
+		client:      client,
+		maxAttempts: maxAttempts,
+		baseDelay:   500 * time.Millisecond,
+		maxDelay:    20 * time.Second,
+	}
+}
+
+// isRetryableError reports whether err is worth retrying.
+// The retryer of the SDK is disabled by main, so the errors which it retries such as connection errors are included.
+func isRetryableError(err error) bool {
+	var throttling *types.ThrottlingException
+	var conflict *types.ConflictException
+	var internal *types.InternalServerException
+	if errors.As(err, &throttling) || errors.As(err, &conflict) || errors.As(err, &internal) {
+		return true
+	}
+	return retry.IsErrorRetryables(retry.DefaultRetryables).IsErrorRetryable(err) == aws.TrueTernary
+}
+
+// backoff returns the duration to wait before the next attempt. attempt starts from 1.
+func (c *retryClient) backoff(attempt int) time.Duration {
+	d := c.maxDelay
+	if attempt < 32 {
+		d = min(c.baseDelay<<(attempt-1), c.maxDelay)
+	}
+	return rand.N(d) + 1
+}
+
+func callWithRetry[I, O any](ctx context.Context, c *retryClient, op string, f func(context.Context, I, ...func(*scheduler.Options)) (O, error), params I, optFns ...func(*scheduler.Options)) (O, error) {
+	for attempt := 1; ; attempt++ {
+		out, err := f(ctx, params, optFns...)
+		if err == nil || attempt >= c.maxAttempts || !isRetryableError(err) {
+			return out, err
+		}
+
+		wait := c.backoff(attempt)
+		log.Printf("%s: retry %d/%d after %s: %v", op, attempt, c.maxAttempts-1, wait, err)
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			var zero O
+			return zero, errors.Join(ctx.Err(), err)
+		case <-t.C:
+		}
+	}
+}
+
+func (c *retryClient) GetScheduleGroup(ctx context.Context, params *scheduler.GetScheduleGroupInput, optFns ...func(*scheduler.Options)) (*scheduler.GetScheduleGroupOutput, error) {
+	return callWithRetry(ctx, c, "GetScheduleGroup", c.client.GetScheduleGroup, params, optFns...)
+}
+
+func (c *retryClient) CreateScheduleGroup(ctx context.Context, params *scheduler.CreateScheduleGroupInput, optFns ...func(*scheduler.Options)) (*scheduler.CreateScheduleGroupOutput, error) {
+	return callWithRetry(ctx, c, "CreateScheduleGroup", c.client.CreateScheduleGroup, params, optFns...)
+}
+
+func (c *retryClient) DeleteScheduleGroup(ctx context.Context, params *scheduler.DeleteScheduleGroupInput, optFns ...func(*scheduler.Options)) (*scheduler.DeleteScheduleGroupOutput, error) {
+	return callWithRetry(ctx, c, "DeleteScheduleGroup", c.client.DeleteScheduleGroup, params, optFns...)
+}
+
+func (c *retryClient) ListScheduleGroups(ctx context.Context, params *scheduler.ListScheduleGroupsInput, optFns ...func(*scheduler.Options)) (*scheduler.ListScheduleGroupsOutput, error) {
+	return callWithRetry(ctx, c, "ListScheduleGroups", c.client.ListScheduleGroups, params, optFns...)
+}
+
+func (c *retryClient) GetSchedule(ctx context.Context, params *scheduler.GetScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.GetScheduleOutput, error) {
+	return callWithRetry(ctx, c, "GetSchedule", c.client.GetSchedule, params, optFns...)
+}
+
+func (c *retryClient) CreateSchedule(ctx context.Context, params *scheduler.CreateScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.CreateScheduleOutput, error) {
+	return callWithRetry(ctx, c, "CreateSchedule", c.client.CreateSchedule, params, optFns...)
+}
+
+func (c *retryClient) UpdateSchedule(ctx context.Context, params *scheduler.UpdateScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.UpdateScheduleOutput, error) {
+	return callWithRetry(ctx, c, "UpdateSchedule", c.client.UpdateSchedule, params, optFns...)
+}
+
+func (c *retryClient) DeleteSchedule(ctx context.Context, params *scheduler.DeleteScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.DeleteScheduleOutput, error) {
+	return callWithRetry(ctx, c, "DeleteSchedule", c.client.DeleteSchedule, params, optFns...)
+}
+
+func (c *retryClient) ListSchedules(ctx context.Context, params *scheduler.ListSchedulesInput, optFns ...func(*scheduler.Options)) (*scheduler.ListSchedulesOutput, error) {
+	return callWithRetry(ctx, c, "ListSchedules", c.client.ListSchedules, params, optFns...)
+}
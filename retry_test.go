@@ -0,0 +1,114 @@
+package ebschedule
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/stretchr/testify/assert"
+	mock_ebschedule "github.com/tckz/ebschedule/mock"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_retryClient(t *testing.T) {
+	newClient := func(cl SchedulerClient, baseDelay time.Duration) *retryClient {
+		return &retryClient{
+			client:      cl,
+			maxAttempts: 3,
+			baseDelay:   baseDelay,
+			maxDelay:    baseDelay * 4,
+		}
+	}
+	params := &scheduler.GetScheduleInput{
+		Name:      aws.String("some-schedule"),
+		GroupName: aws.String("some-group"),
+	}
+
+	t.Run("retry-then-success", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+		gomock.InOrder(
+			cl.EXPECT().GetSchedule(gomock.Any(), params).Return(nil, &types.ThrottlingException{}),
+			cl.EXPECT().GetSchedule(gomock.Any(), params).Return(nil, &types.InternalServerException{}),
+			cl.EXPECT().GetSchedule(gomock.Any(), params).Return(&scheduler.GetScheduleOutput{Name: aws.String("some-schedule")}, nil),
+		)
+
+		out, err := newClient(cl, time.Millisecond).GetSchedule(context.Background(), params)
+		assert.NoError(err)
+		assert.Equal("some-schedule", *out.Name)
+	})
+
+	t.Run("connection-error", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Errors which the SDK retries are retried here instead, because the SDK retryer is disabled.
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+		gomock.InOrder(
+			cl.EXPECT().GetSchedule(gomock.Any(), params).Return(nil, &net.OpError{Op: "dial", Err: errors.New("connection refused")}),
+			cl.EXPECT().GetSchedule(gomock.Any(), params).Return(&scheduler.GetScheduleOutput{Name: aws.String("some-schedule")}, nil),
+		)
+
+		out, err := newClient(cl, time.Millisecond).GetSchedule(context.Background(), params)
+		assert.NoError(err)
+		assert.Equal("some-schedule", *out.Name)
+	})
+
+	t.Run("give-up", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+		cl.EXPECT().GetSchedule(gomock.Any(), params).Return(nil, &types.ConflictException{Message: aws.String("conflict")}).Times(3)
+
+		_, err := newClient(cl, time.Millisecond).GetSchedule(context.Background(), params)
+		assert.EqualError(err, `ConflictException: conflict`)
+	})
+
+	t.Run("not-retryable", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+		cl.EXPECT().GetSchedule(gomock.Any(), params).Return(nil, &types.ResourceNotFoundException{}).Times(1)
+
+		_, err := newClient(cl, time.Millisecond).GetSchedule(context.Background(), params)
+		var notFound *types.ResourceNotFoundException
+		assert.True(errors.As(err, &notFound))
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+		cl.EXPECT().GetSchedule(gomock.Any(), params).
+			DoAndReturn(func(context.Context, *scheduler.GetScheduleInput, ...func(*scheduler.Options)) (*scheduler.GetScheduleOutput, error) {
+				cancel()
+				return nil, &types.ThrottlingException{}
+			}).Times(1)
+
+		_, err := newClient(cl, time.Hour).GetSchedule(ctx, params)
+		assert.ErrorIs(err, context.Canceled)
+		var throttling *types.ThrottlingException
+		assert.True(errors.As(err, &throttling))
+	})
+}
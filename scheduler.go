@@ -4,15 +4,49 @@ package ebschedule
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
 )
 
 type SchedulerClient interface {
 	GetScheduleGroup(ctx context.Context, params *scheduler.GetScheduleGroupInput, optFns ...func(*scheduler.Options)) (*scheduler.GetScheduleGroupOutput, error)
 	CreateScheduleGroup(ctx context.Context, params *scheduler.CreateScheduleGroupInput, optFns ...func(*scheduler.Options)) (*scheduler.CreateScheduleGroupOutput, error)
+	DeleteScheduleGroup(ctx context.Context, params *scheduler.DeleteScheduleGroupInput, optFns ...func(*scheduler.Options)) (*scheduler.DeleteScheduleGroupOutput, error)
+	ListScheduleGroups(ctx context.Context, params *scheduler.ListScheduleGroupsInput, optFns ...func(*scheduler.Options)) (*scheduler.ListScheduleGroupsOutput, error)
 
 	GetSchedule(ctx context.Context, params *scheduler.GetScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.GetScheduleOutput, error)
 	CreateSchedule(ctx context.Context, params *scheduler.CreateScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.CreateScheduleOutput, error)
 	UpdateSchedule(ctx context.Context, params *scheduler.UpdateScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.UpdateScheduleOutput, error)
+	DeleteSchedule(ctx context.Context, params *scheduler.DeleteScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.DeleteScheduleOutput, error)
+	ListSchedules(ctx context.Context, params *scheduler.ListSchedulesInput, optFns ...func(*scheduler.Options)) (*scheduler.ListSchedulesOutput, error)
+}
+
+// listSchedules returns all schedules which match the params over pages.
+func listSchedules(ctx context.Context, client SchedulerClient, params *scheduler.ListSchedulesInput) ([]types.ScheduleSummary, error) {
+	var ret []types.ScheduleSummary
+	p := scheduler.NewListSchedulesPaginator(client, params)
+	for p.HasMorePages() {
+		out, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("scheduler.ListSchedules: %w", err)
+		}
+		ret = append(ret, out.Schedules...)
+	}
+	return ret, nil
+}
+
+// listScheduleGroups returns all schedule groups which match the params over pages.
+func listScheduleGroups(ctx context.Context, client SchedulerClient, params *scheduler.ListScheduleGroupsInput) ([]types.ScheduleGroupSummary, error) {
+	var ret []types.ScheduleGroupSummary
+	p := scheduler.NewListScheduleGroupsPaginator(client, params)
+	for p.HasMorePages() {
+		out, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("scheduler.ListScheduleGroups: %w", err)
+		}
+		ret = append(ret, out.ScheduleGroups...)
+	}
+	return ret, nil
 }
@@ -0,0 +1,164 @@
+package ebschedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+func newEnableCommand(in *CommandInput) *cobra.Command {
+	return newStateCommand(in, "enable", "Enable remote schedules without changing other fields", types.ScheduleStateEnabled)
+}
+
+func newDisableCommand(in *CommandInput) *cobra.Command {
+	return newStateCommand(in, "disable", "Disable remote schedules without changing other fields", types.ScheduleStateDisabled)
+}
+
+func newStateCommand(in *CommandInput, use, short string, state types.ScheduleState) *cobra.Command {
+	return wrapCobra(&cobra.Command{
+		Use:   use,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			patterns, _ := cmd.Flags().GetStringArray(OptSchedule)
+			optName, _ := cmd.Flags().GetString(OptName)
+			optGroup, _ := cmd.Flags().GetString(OptGroup)
+			optNamePrefix, _ := cmd.Flags().GetString(OptNamePrefix)
+			optDryRun, _ := cmd.Flags().GetBool(OptDryRun)
+			optHistoryDir, _ := cmd.Flags().GetString(OptHistoryDir)
+
+			var targets []*inputSchedule
+			switch {
+			case len(patterns) > 0:
+				schs, err := prepareInputSchedules(patterns, loadOptionsFromFlags(cmd))
+				if err != nil {
+					return fmt.Errorf("prepareInputSchedules: %w", err)
+				}
+				targets = schs
+			case optName != "":
+				targets = []*inputSchedule{{
+					Input: &scheduler.CreateScheduleInput{
+						Name:      aws.String(optName),
+						GroupName: aws.String(optGroup),
+					},
+				}}
+			case optNamePrefix != "":
+				sums, err := listSchedules(ctx, in.SchedulerClient, &scheduler.ListSchedulesInput{
+					GroupName:  aws.String(optGroup),
+					NamePrefix: aws.String(optNamePrefix),
+				})
+				if err != nil {
+					return err
+				}
+				if len(sums) == 0 {
+					return fmt.Errorf("no schedule whose name starts with %s in %s", optNamePrefix, optGroup)
+				}
+				targets = lo.Map(sums, func(e types.ScheduleSummary, _ int) *inputSchedule {
+					return &inputSchedule{
+						Input: &scheduler.CreateScheduleInput{
+							Name:      e.Name,
+							GroupName: e.GroupName,
+						},
+					}
+				})
+			default:
+				return fmt.Errorf("either --%s, --%s or --%s must be specified", OptSchedule, OptName, OptNamePrefix)
+			}
+
+			var summary updateSummary
+			var errs []error
+			for _, sch := range targets {
+				res, err := changeScheduleState(ctx, in.SchedulerClient, in.OutWriter, sch, state, optDryRun, optHistoryDir)
+				if err != nil {
+					summary.Failed++
+					errs = append(errs, fmt.Errorf("%s: %w", sch.ID(), err))
+					continue
+				}
+				switch res {
+				case updateResultUpdated:
+					summary.Updated++
+				case updateResultUnchanged:
+					summary.Unchanged++
+				}
+			}
+			if optDryRun {
+				log.Printf("(dry-run) %s", summary.String())
+			} else {
+				log.Print(summary.String())
+			}
+			return errors.Join(errs...)
+		},
+	}, func(cmd *cobra.Command) {
+		addOptionalScheduleFlag(cmd)
+		addHistoryDirFlag(cmd)
+		cmd.Flags().String(OptName, "", "name of the schedule to "+use)
+		cmd.Flags().String(OptGroup, "default", "name of the schedule group")
+		cmd.Flags().String(OptNamePrefix, "", use+" all schedules in the group whose name starts with the prefix")
+		cmd.Flags().Bool(OptDryRun, false, "output API inputs which would be sent instead of updating")
+		cmd.MarkFlagsMutuallyExclusive(OptSchedule, OptName, OptNamePrefix)
+	})
+}
+
+// changeScheduleState updates only State of the remote schedule.
+// UpdateSchedule replaces the whole schedule, so the other fields are taken from the current remote schedule.
+func changeScheduleState(ctx context.Context, client SchedulerClient, w io.Writer, in *inputSchedule, state types.ScheduleState, dryRun bool, historyDir string) (updateResult, error) {
+	cur, err := client.GetSchedule(ctx, &scheduler.GetScheduleInput{
+		Name:      in.Input.Name,
+		GroupName: in.Input.GroupName,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("scheduler.GetSchedule: %w", err)
+	}
+	if cur.State == state {
+		log.Printf("Schedule %s is already %s, skip updating", in.ID(), state)
+		return updateResultUnchanged, nil
+	}
+
+	params, err := remoteToUpdateInput(cur)
+	if err != nil {
+		return 0, fmt.Errorf("remoteToUpdateInput: %w", err)
+	}
+	params.State = state
+
+	if dryRun {
+		log.Printf("(dry-run) Schedule %s would be %s", in.ID(), state)
+		_ = outputResultAsYAML(params, w)
+		return updateResultUpdated, nil
+	}
+	if historyDir != "" {
+		fn, err := saveHistory(historyDir, in.Input.GroupName, in.Input.Name, cur)
+		if err != nil {
+			return 0, fmt.Errorf("saveHistory: %w", err)
+		}
+		log.Printf("Schedule %s is saved to %s", in.ID(), fn)
+	}
+	out, err := client.UpdateSchedule(ctx, params)
+	if err != nil {
+		return 0, fmt.Errorf("scheduler.UpdateSchedule: %w", err)
+	}
+	log.Printf("Schedule %s is %s", in.ID(), state)
+	_ = outputResultAsYAML(out, w)
+	return updateResultUpdated, nil
+}
+
+// remoteToUpdateInput converts the remote schedule to UpdateScheduleInput as it is.
+func remoteToUpdateInput(remote *scheduler.GetScheduleOutput) (*scheduler.UpdateScheduleInput, error) {
+	b, err := json.Marshal(remote)
+	if err != nil {
+		return nil, err
+	}
+	var ret scheduler.UpdateScheduleInput
+	if err := json.Unmarshal(b, &ret); err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
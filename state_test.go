@@ -0,0 +1,225 @@
+package ebschedule
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/assert"
+	mock_ebschedule "github.com/tckz/ebschedule/mock"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_state(t *testing.T) {
+	optsIgnoreUnexported := cmpopts.IgnoreUnexported(
+		scheduler.UpdateScheduleInput{},
+		scheduler.ListSchedulesInput{},
+		types.FlexibleTimeWindow{},
+		types.Target{},
+		types.RetryPolicy{},
+		types.DeadLetterConfig{},
+		types.EcsParameters{},
+		types.NetworkConfiguration{},
+		types.AwsVpcConfiguration{},
+	)
+
+	t.Run("disable", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		remote := remoteNormalForTest()
+		remote.CreationDate = aws.Time(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC))
+		remote.LastModificationDate = aws.Time(time.Date(2023, 2, 3, 4, 5, 6, 0, time.UTC))
+		remote.Description = aws.String("some description")
+		remote.StartDate = aws.Time(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("some-schedule"),
+			GroupName: aws.String("some-group"),
+		}).Return(remote, nil)
+
+		// Every field except State is kept, including Input as a string.
+		cl.EXPECT().UpdateSchedule(gomock.Any(), CmpDiff(&scheduler.UpdateScheduleInput{
+			Name:        aws.String("some-schedule"),
+			GroupName:   aws.String("some-group"),
+			Description: aws.String("some description"),
+			FlexibleTimeWindow: &types.FlexibleTimeWindow{
+				Mode: types.FlexibleTimeWindowModeOff,
+			},
+			ScheduleExpression:         aws.String("cron(*/5 * * * ? *)"),
+			ScheduleExpressionTimezone: aws.String("Asia/Tokyo"),
+			StartDate:                  aws.Time(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+			State:                      types.ScheduleStateDisabled,
+			Target:                     remoteNormalForTest().Target,
+		}, optsIgnoreUnexported)).
+			Return(&scheduler.UpdateScheduleOutput{
+				ScheduleArn: aws.String("arn:aws:scheduler:ap-northeast-1:99999:schedule/some-group/some-schedule"),
+			}, nil)
+
+		dir := t.TempDir()
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		cmd.SetArgs([]string{"disable", "--name", "some-schedule", "--group", "some-group", "--history-dir", dir})
+		err := cmd.ExecuteContext(context.Background())
+
+		assert.NoError(err)
+		assert.Equal(`---
+ScheduleArn: arn:aws:scheduler:ap-northeast-1:99999:schedule/some-group/some-schedule
+ResultMetadata: {}
+`, out.String())
+
+		files, err := listHistory(dir, "some-group", "some-schedule")
+		assert.NoError(err)
+		assert.Len(files, 1)
+	})
+
+	t.Run("already-enabled", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(remoteNormalForTest(), nil)
+		cl.EXPECT().UpdateSchedule(gomock.Any(), gomock.Any()).Times(0)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		cmd.SetArgs([]string{"enable", "--name", "some-schedule", "--group", "some-group", "--history-dir", t.TempDir()})
+		err := cmd.ExecuteContext(context.Background())
+
+		assert.NoError(err)
+		assert.Equal(``, out.String())
+	})
+
+	t.Run("name-prefix", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().ListSchedules(gomock.Any(), CmpDiff(&scheduler.ListSchedulesInput{
+			GroupName:  aws.String("multi-group"),
+			NamePrefix: aws.String("da"),
+		}, optsIgnoreUnexported), gomock.Any()).
+			Return(&scheduler.ListSchedulesOutput{
+				Schedules: []types.ScheduleSummary{
+					{GroupName: aws.String("multi-group"), Name: aws.String("daily")},
+					{GroupName: aws.String("multi-group"), Name: aws.String("daily-2")},
+				},
+			}, nil)
+		disabled := remoteDailyForTest()
+		disabled.State = types.ScheduleStateDisabled
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("daily"),
+			GroupName: aws.String("multi-group"),
+		}).Return(disabled, nil)
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("daily-2"),
+			GroupName: aws.String("multi-group"),
+		}).Return(nil, errors.New("err@GetSchedule"))
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		cmd.SetArgs([]string{"enable", "--name-prefix", "da", "--group", "multi-group", "--dry-run"})
+		err := cmd.ExecuteContext(context.Background())
+
+		assert.EqualError(err, `multi-group/daily-2: scheduler.GetSchedule: err@GetSchedule`)
+		assert.Contains(out.String(), "Name: daily\n")
+		assert.Contains(out.String(), "State: ENABLED\n")
+	})
+
+	t.Run("schedule", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("daily"),
+			GroupName: aws.String("multi-group"),
+		}).Return(remoteDailyForTest(), nil)
+		cl.EXPECT().UpdateSchedule(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, in *scheduler.UpdateScheduleInput, _ ...func(*scheduler.Options)) (*scheduler.UpdateScheduleOutput, error) {
+				assert.Equal(types.ScheduleStateDisabled, in.State)
+				return &scheduler.UpdateScheduleOutput{}, nil
+			})
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		cmd.SetArgs([]string{"disable", "--schedule", "testdata/multi/daily.yaml", "--history-dir", ""})
+		err := cmd.ExecuteContext(context.Background())
+
+		assert.NoError(err)
+	})
+
+	t.Run("err-wo-target", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: mock_ebschedule.NewMockSchedulerClient(ctrl),
+			OutWriter:       bytes.NewBuffer(nil),
+		})
+		cmd.SetArgs([]string{"disable", "--group", "some-group"})
+		err := cmd.ExecuteContext(context.Background())
+
+		assert.EqualError(err, `either --schedule, --name or --name-prefix must be specified`)
+	})
+
+	t.Run("err-multiple-targets", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: mock_ebschedule.NewMockSchedulerClient(ctrl),
+			OutWriter:       bytes.NewBuffer(nil),
+		})
+		cmd.SetArgs([]string{"disable", "--name", "some-schedule", "--name-prefix", "some"})
+		err := cmd.ExecuteContext(context.Background())
+
+		assert.ErrorContains(err, `if any flags in the group [schedule name name-prefix] are set none of the others can be`)
+	})
+}
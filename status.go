@@ -0,0 +1,185 @@
+package ebschedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+type scheduleStatus string
+
+const (
+	scheduleStatusInSync    scheduleStatus = "in-sync"
+	scheduleStatusDrifted   scheduleStatus = "drifted"
+	scheduleStatusMissing   scheduleStatus = "missing"
+	scheduleStatusUnmanaged scheduleStatus = "unmanaged"
+)
+
+// scheduleStatusItem is an element of the output of status.
+type scheduleStatusItem struct {
+	GroupName string
+	Name      string
+	Status    scheduleStatus
+	// File is empty for unmanaged schedules.
+	File string `json:",omitempty"`
+	// Paths are JSON pointers of the fields which differ between the remote schedule and the local one.
+	Paths []string `json:",omitempty"`
+}
+
+type statusSummary struct {
+	InSync    int
+	Drifted   int
+	Missing   int
+	Unmanaged int
+	Failed    int
+}
+
+func (s *statusSummary) String() string {
+	return fmt.Sprintf("%d schedule(s) checked: %d in-sync, %d drifted, %d missing, %d unmanaged, %d failed",
+		s.InSync+s.Drifted+s.Missing+s.Unmanaged+s.Failed, s.InSync, s.Drifted, s.Missing, s.Unmanaged, s.Failed)
+}
+
+func (s *statusSummary) add(st scheduleStatus) {
+	switch st {
+	case scheduleStatusInSync:
+		s.InSync++
+	case scheduleStatusDrifted:
+		s.Drifted++
+	case scheduleStatusMissing:
+		s.Missing++
+	case scheduleStatusUnmanaged:
+		s.Unmanaged++
+	}
+}
+
+func newStatusCommand(in *CommandInput) *cobra.Command {
+	return wrapCobra(&cobra.Command{
+		Use:   "status",
+		Short: "Report drift between local and remote schedules",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			patterns, _ := cmd.Flags().GetStringArray(OptSchedule)
+			optExitCode, _ := cmd.Flags().GetBool(OptExitCode)
+			optOutput, _ := cmd.Flags().GetString(OptOutput)
+			optConcurrency, _ := cmd.Flags().GetInt(OptConcurrency)
+
+			switch optOutput {
+			case outputFormatTable, outputFormatJSON:
+			default:
+				return fmt.Errorf("unknown output format: %s", optOutput)
+			}
+
+			schs, err := prepareInputSchedules(patterns, loadOptionsFromFlags(cmd))
+			if err != nil {
+				return fmt.Errorf("prepareInputSchedules: %w", err)
+			}
+
+			results := runParallel(ctx, optConcurrency, schs, func(ctx context.Context, sch *inputSchedule) (scheduleStatusItem, error) {
+				return localScheduleStatus(ctx, in.SchedulerClient, sch)
+			})
+
+			var summary statusSummary
+			var errs []error
+			items := []scheduleStatusItem{}
+			for i, sch := range schs {
+				if err := results[i].Err; err != nil {
+					summary.Failed++
+					errs = append(errs, fmt.Errorf("%s: %w", sch.ID(), err))
+					continue
+				}
+				items = append(items, results[i].Value)
+			}
+
+			unmanaged, err := unmanagedSchedules(ctx, in.SchedulerClient, schs)
+			if err != nil {
+				summary.Failed++
+				errs = append(errs, fmt.Errorf("unmanagedSchedules: %w", err))
+			}
+			for _, r := range unmanaged {
+				items = append(items, scheduleStatusItem{
+					GroupName: aws.ToString(r.GroupName),
+					Name:      aws.ToString(r.Name),
+					Status:    scheduleStatusUnmanaged,
+				})
+			}
+
+			for _, e := range items {
+				summary.add(e.Status)
+			}
+			log.Print(summary.String())
+
+			switch optOutput {
+			case outputFormatJSON:
+				enc := json.NewEncoder(in.OutWriter)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(items); err != nil {
+					return err
+				}
+			default:
+				if err := outputScheduleStatusAsTable(items, in.OutWriter); err != nil {
+					return err
+				}
+			}
+
+			if len(errs) > 0 {
+				return errors.Join(errs...)
+			}
+			if optExitCode && summary.InSync < len(items) {
+				return &ExitCodeError{Code: ExitCodeDiffFound, Err: errors.New("drift found")}
+			}
+			return nil
+		},
+	}, func(cmd *cobra.Command) {
+		addScheduleFlag(cmd)
+		addConcurrencyFlag(cmd)
+		addIgnorePathFlag(cmd)
+		cmd.Flags().Bool(OptExitCode, false, fmt.Sprintf("exit with %d if any schedule is not in-sync, 1 on errors and 0 otherwise", ExitCodeDiffFound))
+		cmd.Flags().StringP(OptOutput, "o", outputFormatTable, "output format: table or json")
+	})
+}
+
+// localScheduleStatus compares the local schedule with the remote one in the same way as diff.
+func localScheduleStatus(ctx context.Context, client SchedulerClient, sch *inputSchedule) (scheduleStatusItem, error) {
+	item := scheduleStatusItem{
+		GroupName: aws.ToString(sch.Input.GroupName),
+		Name:      aws.ToString(sch.Input.Name),
+		File:      sch.FileName,
+	}
+
+	diff, err := diffSchedule(ctx, client, sch)
+	if err != nil {
+		return item, err
+	}
+	if diff.Remote == nil {
+		item.Status = scheduleStatusMissing
+		return item, nil
+	}
+
+	patch := diff.JSONPatch()
+	if len(patch) == 0 {
+		item.Status = scheduleStatusInSync
+		return item, nil
+	}
+	item.Status = scheduleStatusDrifted
+	item.Paths = lo.Uniq(lo.Map(patch, func(e jsonPatchOperation, _ int) string { return e.Path }))
+	return item, nil
+}
+
+func outputScheduleStatusAsTable(items []scheduleStatusItem, w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "GROUP\tNAME\tSTATUS\tFILE\tPATHS")
+	for _, e := range items {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			e.GroupName, e.Name, e.Status, lo.CoalesceOrEmpty(e.File, "-"), lo.CoalesceOrEmpty(strings.Join(e.Paths, ","), "-"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,172 @@
+package ebschedule
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/stretchr/testify/assert"
+	mock_ebschedule "github.com/tckz/ebschedule/mock"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_status(t *testing.T) {
+	expectRemote := func(cl *mock_ebschedule.MockSchedulerClient) {
+		remote := remoteDailyForTest()
+		remote.State = types.ScheduleStateDisabled
+		remote.Target.RoleArn = aws.String("arn:aws:iam::99999:role/other-role")
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("daily"),
+			GroupName: aws.String("multi-group"),
+		}).Return(remote, nil)
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("hourly"),
+			GroupName: aws.String("multi-group"),
+		}).Return(nil, &types.ResourceNotFoundException{})
+		cl.EXPECT().ListSchedules(gomock.Any(), &scheduler.ListSchedulesInput{
+			GroupName: aws.String("multi-group"),
+		}, gomock.Any()).
+			Return(&scheduler.ListSchedulesOutput{
+				Schedules: []types.ScheduleSummary{
+					{GroupName: aws.String("multi-group"), Name: aws.String("daily")},
+					{GroupName: aws.String("multi-group"), Name: aws.String("stale")},
+				},
+			}, nil)
+	}
+
+	t.Run("table", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+		expectRemote(cl)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		cmd.SetArgs([]string{"status", "--schedule", "testdata/multi", "--exit-code"})
+		err := cmd.ExecuteContext(context.Background())
+
+		var exitCodeErr *ExitCodeError
+		if assert.True(errors.As(err, &exitCodeErr)) {
+			assert.Equal(ExitCodeDiffFound, exitCodeErr.Code)
+		}
+		assert.Equal(`GROUP        NAME    STATUS     FILE                       PATHS
+multi-group  daily   drifted    testdata/multi/daily.yaml  /State,/Target/RoleArn
+multi-group  hourly  missing    testdata/multi/hourly.yml  -
+multi-group  stale   unmanaged  -                          -
+`, out.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+		expectRemote(cl)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		cmd.SetArgs([]string{"status", "--schedule", "testdata/multi", "-o", "json", "--ignore-path", "/Target/RoleArn"})
+		err := cmd.ExecuteContext(context.Background())
+
+		assert.NoError(err)
+		assert.Equal(`[
+  {
+    "GroupName": "multi-group",
+    "Name": "daily",
+    "Status": "drifted",
+    "File": "testdata/multi/daily.yaml",
+    "Paths": [
+      "/State"
+    ]
+  },
+  {
+    "GroupName": "multi-group",
+    "Name": "hourly",
+    "Status": "missing",
+    "File": "testdata/multi/hourly.yml"
+  },
+  {
+    "GroupName": "multi-group",
+    "Name": "stale",
+    "Status": "unmanaged"
+  }
+]
+`, out.String())
+	})
+
+	t.Run("in-sync", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(remoteDailyForTest(), nil)
+		cl.EXPECT().ListSchedules(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&scheduler.ListSchedulesOutput{
+				Schedules: []types.ScheduleSummary{
+					{GroupName: aws.String("multi-group"), Name: aws.String("daily")},
+				},
+			}, nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		cmd.SetArgs([]string{"status", "--schedule", "testdata/multi/daily.yaml", "--exit-code"})
+		err := cmd.ExecuteContext(context.Background())
+
+		assert.NoError(err)
+		assert.Equal(`GROUP        NAME   STATUS   FILE                       PATHS
+multi-group  daily  in-sync  testdata/multi/daily.yaml  -
+`, out.String())
+	})
+
+	t.Run("err@GetSchedule", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(nil, errors.New("err@GetSchedule"))
+		cl.EXPECT().ListSchedules(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&scheduler.ListSchedulesOutput{}, nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		cmd.SetArgs([]string{"status", "--schedule", "testdata/multi/daily.yaml", "--exit-code"})
+		err := cmd.ExecuteContext(context.Background())
+
+		var exitCodeErr *ExitCodeError
+		assert.False(errors.As(err, &exitCodeErr))
+		assert.EqualError(err, `multi-group/daily: scheduler.GetSchedule: err@GetSchedule`)
+	})
+}
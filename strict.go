@@ -0,0 +1,285 @@
+package ebschedule
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// requiredFields are the fields documented as "This member is required" in the SDK.
+// Name of CreateScheduleInput is checked by prepareInputSchedule.
+var requiredFields = map[reflect.Type][]string{
+	reflect.TypeFor[scheduler.CreateScheduleInput]():      {"FlexibleTimeWindow", "ScheduleExpression", "Target"},
+	reflect.TypeFor[types.AwsVpcConfiguration]():          {"Subnets"},
+	reflect.TypeFor[types.CapacityProviderStrategyItem](): {"CapacityProvider"},
+	reflect.TypeFor[types.EcsParameters]():                {"TaskDefinitionArn"},
+	reflect.TypeFor[types.EventBridgeParameters]():        {"DetailType", "Source"},
+	reflect.TypeFor[types.FlexibleTimeWindow]():           {"Mode"},
+	reflect.TypeFor[types.KinesisParameters]():            {"PartitionKey"},
+	reflect.TypeFor[types.SageMakerPipelineParameter]():   {"Name", "Value"},
+	reflect.TypeFor[types.Tag]():                          {"Key", "Value"},
+	reflect.TypeFor[types.Target]():                       {"Arn", "RoleArn"},
+}
+
+// extraFields are the fields which are not in the SDK but accepted by ebschedule, and the types of their values.
+var extraFields = map[reflect.Type]map[string]reflect.Type{
+	reflect.TypeFor[scheduler.CreateScheduleInput](): {scheduleSettingsKey: reflect.TypeFor[scheduleSettings]()},
+	reflect.TypeFor[types.Target]():                  {inputObjectKey: reflect.TypeFor[any]()},
+}
+
+// validateScheduleYAML reports unknown, duplicated and missing required fields of a document of schedule.yaml
+// with their line and column.
+// Keys are matched to fields case-insensitively as encoding/json does.
+func validateScheduleYAML(n ast.Node) error {
+	return validateSchema(n, &schemaValidator{fields: true, required: true, positions: true})
+}
+
+// validateScheduleFields reports unknown and duplicated fields only, for schedule.yaml with overlays and overlays themselves.
+func validateScheduleFields(n ast.Node) error {
+	return validateSchema(n, &schemaValidator{fields: true, positions: true})
+}
+
+// validateMergedSchedule reports unknown, duplicated and missing required fields of the JSON which overlays have been applied to.
+// Fields added by JSON Patch, and keys of different case from the ones of schedule.yaml are only found here.
+// Positions are not reported because they do not correspond to any file.
+func validateMergedSchedule(js []byte) error {
+	b, err := yaml.JSONToYAML(js)
+	if err != nil {
+		return err
+	}
+	f, err := parser.ParseBytes(b, 0)
+	if err != nil {
+		return err
+	}
+	if len(f.Docs) == 0 || f.Docs[0].Body == nil {
+		return nil
+	}
+	return validateSchema(f.Docs[0].Body, &schemaValidator{fields: true, required: true})
+}
+
+func validateSchema(n ast.Node, v *schemaValidator) error {
+	v.anchors = collectAnchors(n)
+	v.validate(n, reflect.TypeFor[scheduler.CreateScheduleInput](), "")
+	return errors.Join(v.errs...)
+}
+
+type schemaValidator struct {
+	// fields reports unknown and duplicated fields.
+	fields bool
+	// required reports missing required fields.
+	required  bool
+	positions bool
+	errs      []error
+	// anchors are the values of anchors in the document, which aliases refer to.
+	anchors map[string]ast.Node
+}
+
+type anchorCollector map[string]ast.Node
+
+func (c anchorCollector) Visit(n ast.Node) ast.Visitor {
+	if a, ok := n.(*ast.AnchorNode); ok {
+		c[a.Name.GetToken().Value] = a.Value
+	}
+	return c
+}
+
+func collectAnchors(n ast.Node) map[string]ast.Node {
+	c := anchorCollector{}
+	ast.Walk(c, n)
+	return c
+}
+
+// resolve returns the node which n stands for, following tags, anchors and aliases.
+// nil is returned for an alias to an unknown anchor.
+func (v *schemaValidator) resolve(n ast.Node) ast.Node {
+	// The depth limits aliases which refer to each other.
+	for range 100 {
+		switch nn := n.(type) {
+		case *ast.TagNode:
+			n = nn.Value
+		case *ast.AnchorNode:
+			n = nn.Value
+		case *ast.AliasNode:
+			n = v.anchors[nn.Value.GetToken().Value]
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+func (v *schemaValidator) errorf(n ast.Node, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if !v.positions {
+		v.errs = append(v.errs, errors.New(msg))
+		return
+	}
+	pos := n.GetToken().Position
+	v.errs = append(v.errs, fmt.Errorf("[%d:%d] %s", pos.Line, pos.Column, msg))
+}
+
+func (v *schemaValidator) validate(n ast.Node, t reflect.Type, path string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	n = v.resolve(n)
+	switch n.(type) {
+	case nil, *ast.NullNode:
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if t == reflect.TypeFor[time.Time]() {
+			return
+		}
+		values, ok := v.mappingValues(n)
+		if !ok {
+			return
+		}
+		v.validateStruct(n, values, t, path)
+	case reflect.Map:
+		values, ok := v.mappingValues(n)
+		if !ok {
+			return
+		}
+		for _, mv := range values {
+			v.validate(mv.Value, t.Elem(), joinSchemaPath(path, mv.Key.GetToken().Value))
+		}
+	case reflect.Slice:
+		seq, ok := n.(*ast.SequenceNode)
+		if !ok {
+			return
+		}
+		for i, e := range seq.Values {
+			v.validate(e, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+}
+
+func (v *schemaValidator) validateStruct(n ast.Node, values []*ast.MappingValueNode, t reflect.Type, path string) {
+	specified := map[string]string{}
+	for _, mv := range values {
+		key := mv.Key.GetToken().Value
+		if name, typ, ok := lookupExtraField(t, key); ok {
+			v.validate(mv.Value, typ, joinSchemaPath(path, name))
+			continue
+		}
+		field, ok := lookupField(t, key)
+		if !ok {
+			if v.fields {
+				v.errorf(mv.Key, "unknown field %q in %s", key, schemaPathName(path))
+			}
+			continue
+		}
+		if prev, ok := specified[field.Name]; ok {
+			if v.fields {
+				v.errorf(mv.Key, "duplicated field %q in %s, already specified as %q", key, schemaPathName(path), prev)
+			}
+			continue
+		}
+		specified[field.Name] = key
+		v.validate(mv.Value, field.Type, joinSchemaPath(path, field.Name))
+	}
+
+	if !v.required {
+		return
+	}
+	// Missing fields are reported at the first key of the mapping.
+	at := n
+	if len(values) > 0 {
+		at = values[0].Key
+	}
+	for _, name := range requiredFields[t] {
+		if _, ok := specified[name]; !ok {
+			v.errorf(at, "missing required field %q in %s", name, schemaPathName(path))
+		}
+	}
+}
+
+// lookupField finds exported field of the struct by the key as encoding/json does, preferring an exact match.
+func lookupField(t reflect.Type, key string) (reflect.StructField, bool) {
+	if f, ok := t.FieldByName(key); ok && f.IsExported() && len(f.Index) == 1 {
+		return f, true
+	}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if f.IsExported() && strings.EqualFold(f.Name, key) {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func lookupExtraField(t reflect.Type, key string) (string, reflect.Type, bool) {
+	for name, typ := range extraFields[t] {
+		if strings.EqualFold(name, key) {
+			return name, typ, true
+		}
+	}
+	return "", nil, false
+}
+
+// mappingValues returns the values of the mapping, expanding merge keys as the decoder does.
+// The keys of the mapping itself take precedence over the merged ones, and earlier merged ones over later ones.
+func (v *schemaValidator) mappingValues(n ast.Node) ([]*ast.MappingValueNode, bool) {
+	var values []*ast.MappingValueNode
+	switch nn := v.resolve(n).(type) {
+	case *ast.MappingNode:
+		values = nn.Values
+	case *ast.MappingValueNode:
+		values = []*ast.MappingValueNode{nn}
+	default:
+		return nil, false
+	}
+
+	var ret, merges []*ast.MappingValueNode
+	keys := map[string]bool{}
+	for _, mv := range values {
+		if mv.Key.IsMergeKey() {
+			merges = append(merges, mv)
+			continue
+		}
+		keys[mv.Key.GetToken().Value] = true
+		ret = append(ret, mv)
+	}
+	for _, mv := range merges {
+		sources := []ast.Node{mv.Value}
+		if seq, ok := v.resolve(mv.Value).(*ast.SequenceNode); ok {
+			sources = seq.Values
+		}
+		for _, src := range sources {
+			merged, _ := v.mappingValues(src)
+			for _, e := range merged {
+				if key := e.Key.GetToken().Value; !keys[key] {
+					keys[key] = true
+					ret = append(ret, e)
+				}
+			}
+		}
+	}
+	return ret, true
+}
+
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func schemaPathName(path string) string {
+	if path == "" {
+		return "top level"
+	}
+	return path
+}
@@ -0,0 +1,172 @@
+package ebschedule
+
+import (
+	"testing"
+
+	"github.com/goccy/go-yaml/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validateScheduleYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			src: `
+Name: some-schedule
+ScheduleExpression: rate(1 day)
+StartDate: 2024-01-01T00:00:00Z
+FlexibleTimeWindow:
+  Mode: 'OFF'
+Target:
+  Arn: arn
+  RoleArn: role
+  EcsParameters:
+    TaskDefinitionArn: def
+    NetworkConfiguration:
+      awsvpcConfiguration:
+        Subnets: [subnet-xxxxx]
+    Tags:
+      - some: value
+`,
+		},
+		{
+			name: "case-insensitive",
+			src: `
+name: some-schedule
+scheduleExpression: rate(1 day)
+flexibleTimeWindow: {mode: 'OFF'}
+target: {arn: arn, roleArn: role}
+`,
+		},
+		{
+			name: "unknown",
+			src: `
+Name: some-schedule
+ScheduleExpression: rate(1 day)
+FlexibleTimeWindow:
+  Mode: 'OFF'
+Target:
+  Arn: arn
+  RoleArn: role
+  RetryPolcy:
+    MaximumRetryAttempts: 2
+  EcsParameters:
+    TaskDefinitionArn: def
+    CapacityProviderStrategy:
+      - CapacityProvider: FARGATE
+        Wieght: 1
+`,
+			wantErr: `[9:3] unknown field "RetryPolcy" in Target
+[15:9] unknown field "Wieght" in Target.EcsParameters.CapacityProviderStrategy[0]`,
+		},
+		{
+			name: "duplicated",
+			src: `
+Name: some-schedule
+ScheduleExpression: rate(1 day)
+FlexibleTimeWindow:
+  Mode: 'OFF'
+Target:
+  Arn: arn
+  RoleArn: role
+  EcsParameters:
+    TaskDefinitionArn: def
+    NetworkConfiguration:
+      AwsvpcConfiguration:
+        Subnets: [subnet-xxxxx]
+      awsvpcConfiguration:
+        Subnets: [subnet-yyyyy]
+`,
+			wantErr: `[14:7] duplicated field "awsvpcConfiguration" in Target.EcsParameters.NetworkConfiguration, already specified as "AwsvpcConfiguration"`,
+		},
+		{
+			name: "missing",
+			src: `
+Name: some-schedule
+ScheduleExpression: rate(1 day)
+FlexibleTimeWindow:
+  MaximumWindowInMinutes: 5
+Target:
+  Arn: arn
+`,
+			wantErr: `[5:3] missing required field "Mode" in FlexibleTimeWindow
+[7:3] missing required field "RoleArn" in Target`,
+		},
+		{
+			name: "settings",
+			src: `
+Ebschedule:
+  IgnorePaths: [/State]
+  IgnorePath: [/KmsKeyArn]
+Name: some-schedule
+ScheduleExpression: rate(1 day)
+FlexibleTimeWindow: {Mode: 'OFF'}
+Target: {Arn: arn, RoleArn: role}
+`,
+			wantErr: `[4:3] unknown field "IgnorePath" in Ebschedule`,
+		},
+		{
+			name: "merge-key",
+			src: `
+Name: some-schedule
+ScheduleExpression: rate(1 day)
+FlexibleTimeWindow: {Mode: 'OFF'}
+Target:
+  <<: [{Arn: arn}, {Arn: other, RoleArn: role}]
+  EcsParameters:
+    TaskDefinitionArn: def
+    PlacementConstraints:
+      - &pc {Type: distinctInstance}
+      - *pc
+`,
+		},
+		{
+			name: "merge-key-errors",
+			src: `
+Name: some-schedule
+ScheduleExpression: rate(1 day)
+FlexibleTimeWindow: {Mode: 'OFF'}
+Target:
+  <<: {Arn: arn, RoleArn: role}
+  roleArn: other
+  EcsParameters:
+    TaskDefinitionArn: def
+    PlacementConstraints:
+      - &pc {Type: distinctInstance}
+      - <<: *pc
+        Typo: x
+`,
+			wantErr: `[13:9] unknown field "Typo" in Target.EcsParameters.PlacementConstraints[1]
+[6:18] duplicated field "RoleArn" in Target, already specified as "roleArn"`,
+		},
+		{
+			name: "missing top level",
+			src: `
+Name: some-schedule
+`,
+			wantErr: `[2:1] missing required field "FlexibleTimeWindow" in top level
+[2:1] missing required field "ScheduleExpression" in top level
+[2:1] missing required field "Target" in top level`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			f, err := parser.ParseBytes([]byte(tt.src), 0)
+			if !assert.NoError(err) {
+				return
+			}
+			err = validateScheduleYAML(f.Docs[0].Body)
+			if tt.wantErr == "" {
+				assert.NoError(err)
+			} else {
+				assert.EqualError(err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,86 +1,376 @@
 package ebschedule
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"slices"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/scheduler"
 	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
 	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 )
 
+// errScheduleConflict is returned when the remote schedule has been modified by others.
+var errScheduleConflict = errors.New("schedule conflict")
+
+type updateResult int
+
+const (
+	updateResultCreated updateResult = iota
+	updateResultUpdated
+	updateResultUnchanged
+)
+
+type updateSummary struct {
+	Created   int
+	Updated   int
+	Unchanged int
+	Deleted   int
+	Failed    int
+}
+
+func (s *updateSummary) String() string {
+	return fmt.Sprintf("%d schedule(s) processed: %d created, %d updated, %d unchanged, %d deleted, %d failed",
+		s.Created+s.Updated+s.Unchanged+s.Deleted+s.Failed, s.Created, s.Updated, s.Unchanged, s.Deleted, s.Failed)
+}
+
 func newUpdateCommand(in *CommandInput) *cobra.Command {
 	return wrapCobra(&cobra.Command{
 		Use:   "update",
 		Short: "Update or create schedule",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
-			fn := cmd.Flag(OptSchedule).Value.String()
+			patterns, _ := cmd.Flags().GetStringArray(OptSchedule)
 			optCreateScheduleGroup, _ := cmd.Flags().GetBool(OptCreateScheduleGroup)
+			optPrune, _ := cmd.Flags().GetBool(OptPrune)
+			optDryRun, _ := cmd.Flags().GetBool(OptDryRun)
+			optConcurrency, _ := cmd.Flags().GetInt(OptConcurrency)
+			optExpectUnchangedSince, _ := cmd.Flags().GetString(OptExpectUnchangedSince)
+			optHistoryDir, _ := cmd.Flags().GetString(OptHistoryDir)
 
-			sch, err := prepareInputSchedule(fn)
-			if err != nil {
-				return fmt.Errorf("prepareInputSchedule: %w", err)
-			}
-
-			_, err = in.SchedulerClient.GetScheduleGroup(ctx, &scheduler.GetScheduleGroupInput{
-				Name: sch.GroupName,
-			})
-			if err != nil {
-				var notFound *types.ResourceNotFoundException
-				if !errors.As(err, &notFound) || !optCreateScheduleGroup {
-					return fmt.Errorf("scheduler.GetScheduleGroup: %w", err)
-				}
-				log.Printf("ScheduleGroup %s does not exist, try to create", *sch.GroupName)
-				out, err := in.SchedulerClient.CreateScheduleGroup(ctx, &scheduler.CreateScheduleGroupInput{
-					Name: sch.GroupName,
-				})
+			var expectUnchangedSince *time.Time
+			if optExpectUnchangedSince != "" {
+				t, err := time.Parse(time.RFC3339, optExpectUnchangedSince)
 				if err != nil {
-					return fmt.Errorf("scheduler.CreateScheduleGroup: %w", err)
+					return fmt.Errorf("--%s: %w", OptExpectUnchangedSince, err)
 				}
-				_ = outputResultAsYAML(out, in.OutWriter)
+				expectUnchangedSince = &t
 			}
 
-			var out any
-			_, err = in.SchedulerClient.GetSchedule(ctx, &scheduler.GetScheduleInput{
-				Name:      sch.Name,
-				GroupName: sch.GroupName,
-			})
+			schs, err := prepareInputSchedules(patterns, loadOptionsFromFlags(cmd))
 			if err != nil {
-				var notFound *types.ResourceNotFoundException
-				if !errors.As(err, &notFound) {
-					return fmt.Errorf("scheduler.GetSchedule: %w", err)
-				}
+				return fmt.Errorf("prepareInputSchedules: %w", err)
+			}
 
-				out, err = in.SchedulerClient.CreateSchedule(ctx, sch)
-				if err != nil {
-					return err
-				}
-			} else {
-				b, err := json.Marshal(sch)
-				if err != nil {
-					return err
-				}
-				var updateInput scheduler.UpdateScheduleInput
-				err = json.Unmarshal(b, &updateInput)
-				if err != nil {
-					return err
-				}
+			u := &updater{
+				client:               in.SchedulerClient,
+				out:                  in.OutWriter,
+				createScheduleGroup:  optCreateScheduleGroup,
+				dryRun:               optDryRun,
+				concurrency:          optConcurrency,
+				expectUnchangedSince: expectUnchangedSince,
+				historyDir:           optHistoryDir,
+				checkedGroups:        map[string]error{},
+			}
 
-				out, err = in.SchedulerClient.UpdateSchedule(ctx, &updateInput)
-				if err != nil {
-					return err
+			summary, errs := u.updateAll(ctx, schs)
+
+			if optPrune {
+				if len(errs) > 0 {
+					log.Printf("Skip pruning because some schedules failed")
+				} else {
+					deleted, err := u.prune(ctx, schs)
+					summary.Deleted += deleted
+					if err != nil {
+						summary.Failed++
+						errs = append(errs, fmt.Errorf("prune: %w", err))
+					}
 				}
 			}
-			_ = outputResultAsYAML(out, in.OutWriter)
-			return nil
+			if optDryRun {
+				log.Printf("(dry-run) %s", summary.String())
+			} else {
+				log.Print(summary.String())
+			}
+
+			return errors.Join(errs...)
 		},
 	}, func(cmd *cobra.Command) {
-		cmd.Flags().String(OptSchedule, "", "path/to/schedule.yaml")
-		lo.Must0(cmd.MarkFlagRequired(OptSchedule))
+		addScheduleFlag(cmd)
+		addConcurrencyFlag(cmd)
+		addIgnorePathFlag(cmd)
+		addHistoryDirFlag(cmd)
 		cmd.Flags().Bool(OptCreateScheduleGroup, true, "create schedule group if not exist")
+		cmd.Flags().Bool(OptPrune, false, "delete remote schedules which do not exist locally, in the schedule groups of local schedules")
+		cmd.Flags().Bool(OptDryRun, false, "output API inputs which would be sent instead of creating, updating or deleting")
+		cmd.Flags().String(OptExpectUnchangedSince, "", "abort updating a schedule whose LastModificationDate is after this RFC3339 time")
+	})
+}
+
+type updater struct {
+	client              SchedulerClient
+	out                 io.Writer
+	createScheduleGroup bool
+	// dryRun outputs inputs of mutating APIs instead of calling them.
+	dryRun bool
+	// concurrency is the number of schedules processed in parallel.
+	concurrency int
+	// expectUnchangedSince makes update fail with errScheduleConflict when the remote schedule has been modified after it.
+	expectUnchangedSince *time.Time
+	// historyDir is the directory to save remote schedules before updating them. Empty to disable.
+	historyDir string
+	// checkedGroups holds the result of ensureScheduleGroup for each group name, so that each group is checked once.
+	checkedGroups map[string]error
+}
+
+func (u *updater) ensureScheduleGroup(ctx context.Context, name *string) error {
+	if err, ok := u.checkedGroups[*name]; ok {
+		return err
+	}
+
+	err := func() error {
+		_, err := u.client.GetScheduleGroup(ctx, &scheduler.GetScheduleGroupInput{
+			Name: name,
+		})
+		if err != nil {
+			var notFound *types.ResourceNotFoundException
+			if !errors.As(err, &notFound) || !u.createScheduleGroup {
+				return fmt.Errorf("scheduler.GetScheduleGroup: %w", err)
+			}
+			log.Printf("ScheduleGroup %s does not exist, try to create", *name)
+			params := &scheduler.CreateScheduleGroupInput{
+				Name: name,
+			}
+			if u.dryRun {
+				_ = outputResultAsYAML(params, u.out)
+				return nil
+			}
+			out, err := u.client.CreateScheduleGroup(ctx, params)
+			if err != nil {
+				return fmt.Errorf("scheduler.CreateScheduleGroup: %w", err)
+			}
+			_ = outputResultAsYAML(out, u.out)
+		}
+		return nil
+	}()
+	u.checkedGroups[*name] = err
+	return err
+}
+
+// updateAll creates or updates the schedules.
+// Schedule groups are ensured in advance sequentially, then schedules are processed in parallel.
+// Outputs are written in the order of schs.
+func (u *updater) updateAll(ctx context.Context, schs []*inputSchedule) (updateSummary, []error) {
+	for _, sch := range schs {
+		_ = u.ensureScheduleGroup(ctx, sch.Input.GroupName)
+	}
+
+	type outcome struct {
+		res updateResult
+		out bytes.Buffer
+	}
+	results := runParallel(ctx, u.concurrency, schs, func(ctx context.Context, sch *inputSchedule) (*outcome, error) {
+		o := &outcome{}
+		if err := u.checkedGroups[*sch.Input.GroupName]; err != nil {
+			return o, err
+		}
+		res, err := u.update(ctx, &o.out, sch)
+		o.res = res
+		return o, err
 	})
+
+	var summary updateSummary
+	var errs []error
+	for i, r := range results {
+		_, _ = r.Value.out.WriteTo(u.out)
+		if r.Err != nil {
+			summary.Failed++
+			errs = append(errs, fmt.Errorf("%s: %w", schs[i].ID(), r.Err))
+			continue
+		}
+		switch r.Value.res {
+		case updateResultCreated:
+			summary.Created++
+		case updateResultUpdated:
+			summary.Updated++
+		case updateResultUnchanged:
+			summary.Unchanged++
+		}
+	}
+	return summary, errs
+}
+
+// update creates the schedule if it does not exist remotely.
+// Otherwise it updates the schedule only when it differs from the remote one, after writing the diff to w.
+func (u *updater) update(ctx context.Context, w io.Writer, in *inputSchedule) (updateResult, error) {
+	sch := in.Input
+
+	diff, err := diffSchedule(ctx, u.client, in)
+	if err != nil {
+		return 0, err
+	}
+	curSch := diff.Remote
+	if curSch == nil {
+		if u.dryRun {
+			log.Printf("(dry-run) Schedule %s would be created", in.ID())
+			_ = outputResultAsYAML(sch, w)
+			return updateResultCreated, nil
+		}
+		out, err := u.client.CreateSchedule(ctx, sch)
+		if err != nil {
+			return 0, err
+		}
+		_ = outputResultAsYAML(out, w)
+		return updateResultCreated, nil
+	}
+
+	if len(diff.JSONPatch()) == 0 {
+		log.Printf("Schedule %s is unchanged, skip updating", in.ID())
+		return updateResultUnchanged, nil
+	}
+	if err := checkUnchangedSince(curSch, u.expectUnchangedSince); err != nil {
+		return 0, err
+	}
+	unified, err := diff.Unified()
+	if err != nil {
+		return 0, err
+	}
+	fmt.Fprint(w, coloredDiff(unified))
+
+	updateInput, err := toUpdateInput(sch, curSch, in.IgnorePaths)
+	if err != nil {
+		return 0, err
+	}
+
+	if u.dryRun {
+		log.Printf("(dry-run) Schedule %s would be updated", in.ID())
+		_ = outputResultAsYAML(updateInput, w)
+		return updateResultUpdated, nil
+	}
+	if u.historyDir != "" {
+		fn, err := saveHistory(u.historyDir, sch.GroupName, sch.Name, curSch)
+		if err != nil {
+			return 0, fmt.Errorf("saveHistory: %w", err)
+		}
+		log.Printf("Schedule %s is saved to %s", in.ID(), fn)
+	}
+	out, err := u.client.UpdateSchedule(ctx, updateInput)
+	if err != nil {
+		return 0, err
+	}
+	_ = outputResultAsYAML(out, w)
+	return updateResultUpdated, nil
+}
+
+// checkUnchangedSince returns errScheduleConflict if the remote schedule has been modified after since.
+// LastModificationDate is compared in seconds, because since may be truncated so.
+func checkUnchangedSince(remote *scheduler.GetScheduleOutput, since *time.Time) error {
+	if since == nil {
+		return nil
+	}
+	modified := lo.FromPtr(remote.LastModificationDate)
+	if modified.Truncate(time.Second).After(*since) {
+		return fmt.Errorf("%w: remote schedule has been modified since %s: LastModificationDate=%s",
+			errScheduleConflict, since.Format(time.RFC3339), modified.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// toUpdateInput converts the local schedule to UpdateScheduleInput.
+// The remote values are kept for the fields at ignorePaths.
+func toUpdateInput(sch *scheduler.CreateScheduleInput, remote *scheduler.GetScheduleOutput, ignorePaths []string) (*scheduler.UpdateScheduleInput, error) {
+	if len(ignorePaths) > 0 {
+		return updateInputKeepingIgnored(sch, remote, ignorePaths)
+	}
+
+	b, err := json.Marshal(sch)
+	if err != nil {
+		return nil, err
+	}
+	var updateInput scheduler.UpdateScheduleInput
+	err = json.Unmarshal(b, &updateInput)
+	if err != nil {
+		return nil, err
+	}
+	return &updateInput, nil
+}
+
+// prune deletes remote schedules that have no local counterpart.
+func (u *updater) prune(ctx context.Context, local []*inputSchedule) (int, error) {
+	remote, err := unmanagedSchedules(ctx, u.client, local)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, r := range remote {
+		id := scheduleID(r.GroupName, r.Name)
+		log.Printf("Schedule %s does not exist locally, delete it", id)
+		params := &scheduler.DeleteScheduleInput{
+			Name:      r.Name,
+			GroupName: r.GroupName,
+		}
+		if u.dryRun {
+			_ = outputResultAsYAML(params, u.out)
+			deleted++
+			continue
+		}
+		if u.historyDir != "" {
+			cur, err := u.client.GetSchedule(ctx, &scheduler.GetScheduleInput{
+				Name:      r.Name,
+				GroupName: r.GroupName,
+			})
+			if err != nil {
+				return deleted, fmt.Errorf("%s: scheduler.GetSchedule: %w", id, err)
+			}
+			fn, err := saveHistory(u.historyDir, r.GroupName, r.Name, cur)
+			if err != nil {
+				return deleted, fmt.Errorf("%s: saveHistory: %w", id, err)
+			}
+			log.Printf("Schedule %s is saved to %s", id, fn)
+		}
+		_, err := u.client.DeleteSchedule(ctx, params)
+		if err != nil {
+			return deleted, fmt.Errorf("%s: scheduler.DeleteSchedule: %w", id, err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// unmanagedSchedules returns remote schedules that have no local counterpart.
+// Only the schedule groups which local schedules belong to are examined.
+func unmanagedSchedules(ctx context.Context, client SchedulerClient, local []*inputSchedule) ([]types.ScheduleSummary, error) {
+	localIDs := map[string]bool{}
+	var groups []string
+	for _, sch := range local {
+		if !slices.Contains(groups, *sch.Input.GroupName) {
+			groups = append(groups, *sch.Input.GroupName)
+		}
+		localIDs[sch.ID()] = true
+	}
+
+	var ret []types.ScheduleSummary
+	for _, g := range groups {
+		remote, err := listSchedules(ctx, client, &scheduler.ListSchedulesInput{
+			GroupName: aws.String(g),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range remote {
+			if !localIDs[scheduleID(r.GroupName, r.Name)] {
+				ret = append(ret, r)
+			}
+		}
+	}
+	return ret, nil
 }
@@ -5,7 +5,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/scheduler"
@@ -44,6 +47,47 @@ func CmpDiff(expected any, opts ...cmp.Option) gomock.Matcher {
 	}
 }
 
+// remoteNormalForTest returns remote schedule equivalent to testdata/update/normal.yml except ScheduleExpression.
+func remoteNormalForTest() *scheduler.GetScheduleOutput {
+	return &scheduler.GetScheduleOutput{
+		Arn:       aws.String("arn:aws:scheduler:ap-northeast-1:99999:schedule/some-group/some-schedule"),
+		Name:      aws.String("some-schedule"),
+		GroupName: aws.String("some-group"),
+		FlexibleTimeWindow: &types.FlexibleTimeWindow{
+			Mode: types.FlexibleTimeWindowModeOff,
+		},
+		ScheduleExpression:         aws.String("cron(*/5 * * * ? *)"),
+		ScheduleExpressionTimezone: aws.String("Asia/Tokyo"),
+		State:                      types.ScheduleStateEnabled,
+		Target: &types.Target{
+			Arn:     aws.String("arn:aws:ecs:ap-northeast-1:99999:cluster/some-cluster"),
+			RoleArn: aws.String("arn:aws:iam::99999:role/some-scheduler-role"),
+			DeadLetterConfig: &types.DeadLetterConfig{
+				Arn: aws.String("arn:aws:sqs:ap-northeast-1:99999:some-dlq"),
+			},
+			EcsParameters: &types.EcsParameters{
+				TaskDefinitionArn:    aws.String("arn:aws:ecs:ap-northeast-1:99999:task-definition/some-def"),
+				EnableECSManagedTags: aws.Bool(true),
+				EnableExecuteCommand: aws.Bool(false),
+				LaunchType:           types.LaunchTypeFargate,
+				NetworkConfiguration: &types.NetworkConfiguration{
+					AwsvpcConfiguration: &types.AwsVpcConfiguration{
+						Subnets:        []string{"subnet-xxxxx", "subnet-yyyyy"},
+						AssignPublicIp: types.AssignPublicIpEnabled,
+						SecurityGroups: []string{"sg-xxxxx"},
+					},
+				},
+				TaskCount: aws.Int32(1),
+			},
+			Input: aws.String(`{"containerOverrides":[{"name":"hello-task","command":["ya","yo"]}]}`),
+			RetryPolicy: &types.RetryPolicy{
+				MaximumEventAgeInSeconds: aws.Int32(600),
+				MaximumRetryAttempts:     aws.Int32(2),
+			},
+		},
+	}
+}
+
 func Test_update(t *testing.T) {
 
 	optsIgnoreUnexported := cmpopts.IgnoreUnexported(
@@ -80,7 +124,7 @@ func Test_update(t *testing.T) {
 		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
 			Name:      aws.String("some-schedule"),
 			GroupName: aws.String("some-group"),
-		}).Return(&scheduler.GetScheduleOutput{}, nil)
+		}).Return(remoteNormalForTest(), nil)
 
 		cl.EXPECT().UpdateSchedule(gomock.Any(), CmpDiff(&scheduler.UpdateScheduleInput{
 			Name: aws.String("some-schedule"),
@@ -130,16 +174,97 @@ func Test_update(t *testing.T) {
 			OutWriter:       out,
 		})
 		ctx := context.Background()
-		cmd.SetArgs([]string{"update", "--schedule", "testdata/update/normal.yml"})
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/update/normal.yml", "--history-dir", t.TempDir()})
 		err := cmd.ExecuteContext(ctx)
 
 		assert.NoError(err)
-		assert.Equal(`---
+		assert.Equal(`--- arn:aws:scheduler:ap-northeast-1:99999:schedule/some-group/some-schedule
++++ testdata/update/normal.yml
+@@ -3,7 +3,7 @@
+   Mode: "OFF"
+ GroupName: some-group
+ Name: some-schedule
+-ScheduleExpression: cron(*/5 * * * ? *)
++ScheduleExpression: cron(*/3 * * * ? *)
+ ScheduleExpressionTimezone: Asia/Tokyo
+ State: ENABLED
+ Target:
+
+---
 ScheduleArn: arn:aws:scheduler:ap-northeast-1:99999:schedule/some-group/some-schedule
 ResultMetadata: {}
 `, out.String())
 	})
 
+	t.Run("unchanged", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetScheduleGroup(gomock.Any(), gomock.Any()).
+			Return(&scheduler.GetScheduleGroupOutput{Name: aws.String("some-group")}, nil)
+
+		// The remote schedule is filled with the defaults which are omitted locally.
+		remote := remoteNormalForTest()
+		remote.ScheduleExpression = aws.String("cron(*/3 * * * ? *)")
+		remote.ActionAfterCompletion = types.ActionAfterCompletionNone
+		remote.KmsKeyArn = nil
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(remote, nil)
+		cl.EXPECT().UpdateSchedule(gomock.Any(), gomock.Any()).Times(0)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/update/normal.yml"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+		assert.Equal(``, out.String())
+	})
+
+	t.Run("empty-input-value", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetScheduleGroup(gomock.Any(), gomock.Any()).
+			Return(&scheduler.GetScheduleGroupOutput{Name: aws.String("multi-group")}, nil)
+
+		// Only the empty array in Input differs, which is the data sent to the target.
+		remote := remoteDailyForTest()
+		remote.Target.Input = aws.String(`{"action":"cleanup"}`)
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(remote, nil)
+		cl.EXPECT().UpdateSchedule(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, in *scheduler.UpdateScheduleInput, _ ...func(*scheduler.Options)) (*scheduler.UpdateScheduleOutput, error) {
+				assert.Equal(`{"action":"cleanup","targets":[]}`, aws.ToString(in.Target.Input))
+				return &scheduler.UpdateScheduleOutput{}, nil
+			})
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/update/empty-input-value.yml", "--history-dir", ""})
+		err := cmd.ExecuteContext(context.Background())
+
+		assert.NoError(err)
+		assert.Contains(out.String(), "+    targets: []\n")
+	})
+
 	t.Run("create-sch", func(t *testing.T) {
 		assert := assert.New(t)
 
@@ -333,7 +458,7 @@ ResultMetadata: {}
 		})
 		err := cmd.ExecuteContext(ctx)
 
-		assert.EqualError(err, `scheduler.GetScheduleGroup: ResourceNotFoundException: `)
+		assert.EqualError(err, `some-group/some-schedule: scheduler.GetScheduleGroup: ResourceNotFoundException: `)
 		assert.Equal(``, out.String())
 	})
 
@@ -356,10 +481,166 @@ ResultMetadata: {}
 		cmd.SetArgs([]string{"update", "--schedule", "testdata/update/err-wo-name.yml"})
 		err := cmd.ExecuteContext(ctx)
 
-		assert.EqualError(err, `prepareInputSchedule: Name must be specified`)
+		assert.EqualError(err, `prepareInputSchedules: testdata/update/err-wo-name.yml: Name must be specified`)
+		assert.Equal(``, out.String())
+	})
+
+	t.Run("err-expression", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/update/err-expression.yml"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.EqualError(err, `prepareInputSchedules: testdata/update/err-expression.yml: ScheduleExpression: "cron(0 3 * * * *)": one of Day-of-month or Day-of-week must be "?", but Day-of-month="*", Day-of-week="*"`)
+		assert.Equal(``, out.String())
+	})
+
+	t.Run("err-strict", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/update/err-strict.yml"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.EqualError(err, `prepareInputSchedules: testdata/update/err-strict.yml: validateScheduleYAML: [8:3] unknown field "RetryPolcy" in Target
+[7:3] missing required field "RoleArn" in Target`)
 		assert.Equal(``, out.String())
 	})
 
+	t.Run("no-strict", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetScheduleGroup(gomock.Any(), gomock.Any()).
+			Return(&scheduler.GetScheduleGroupOutput{}, nil)
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).
+			Return(nil, &types.ResourceNotFoundException{})
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/update/err-strict.yml", "--strict=false", "--dry-run"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+		assert.Contains(out.String(), "Name: some-schedule\n")
+	})
+
+	t.Run("expect-unchanged-since", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetScheduleGroup(gomock.Any(), gomock.Any()).
+			Return(&scheduler.GetScheduleGroupOutput{Name: aws.String("some-group")}, nil)
+		remote := remoteNormalForTest()
+		remote.LastModificationDate = aws.Time(time.Date(2024, 1, 2, 3, 4, 5, 678000000, time.UTC))
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(remote, nil)
+		cl.EXPECT().UpdateSchedule(gomock.Any(), gomock.Any()).
+			Return(&scheduler.UpdateScheduleOutput{
+				ScheduleArn: aws.String("arn:aws:scheduler:ap-northeast-1:99999:schedule/some-group/some-schedule"),
+			}, nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/update/normal.yml", "--expect-unchanged-since", "2024-01-02T12:04:05+09:00", "--history-dir", t.TempDir()})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+	})
+
+	t.Run("err-conflict", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetScheduleGroup(gomock.Any(), gomock.Any()).
+			Return(&scheduler.GetScheduleGroupOutput{Name: aws.String("some-group")}, nil)
+		remote := remoteNormalForTest()
+		remote.LastModificationDate = aws.Time(time.Date(2024, 1, 2, 3, 4, 6, 0, time.UTC))
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(remote, nil)
+		cl.EXPECT().UpdateSchedule(gomock.Any(), gomock.Any()).Times(0)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/update/normal.yml", "--expect-unchanged-since", "2024-01-02T03:04:05Z"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.ErrorIs(err, errScheduleConflict)
+		assert.EqualError(err, `some-group/some-schedule: schedule conflict: remote schedule has been modified since 2024-01-02T03:04:05Z: LastModificationDate=2024-01-02T03:04:06Z`)
+		assert.Equal(``, out.String())
+	})
+
+	t.Run("err-expect-unchanged-since", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: mock_ebschedule.NewMockSchedulerClient(ctrl),
+			OutWriter:       bytes.NewBuffer(nil),
+		})
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/update/normal.yml", "--expect-unchanged-since", "2024-01-02"})
+		err := cmd.ExecuteContext(context.Background())
+
+		assert.ErrorContains(err, `--expect-unchanged-since: parsing time "2024-01-02"`)
+	})
+
 	t.Run("err@GetScheduleGroup", func(t *testing.T) {
 		assert := assert.New(t)
 
@@ -383,8 +664,412 @@ ResultMetadata: {}
 		cmd.SetArgs([]string{"update", "--schedule", "testdata/update/omit-group.yml"})
 		err := cmd.ExecuteContext(ctx)
 
-		assert.EqualError(err, `scheduler.GetScheduleGroup: err@GetScheduleGroup`)
+		assert.EqualError(err, `default/some-schedule: scheduler.GetScheduleGroup: err@GetScheduleGroup`)
+		assert.Equal(``, out.String())
+	})
+
+}
+
+func Test_update_multi(t *testing.T) {
+	t.Run("dir", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		// ScheduleGroup is checked only once.
+		cl.EXPECT().GetScheduleGroup(gomock.Any(),
+			&scheduler.GetScheduleGroupInput{Name: aws.String("multi-group")}).
+			Return(&scheduler.GetScheduleGroupOutput{Name: aws.String("multi-group")}, nil)
+
+		gomock.InOrder(
+			cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+				Name:      aws.String("daily"),
+				GroupName: aws.String("multi-group"),
+			}).Return(nil, &types.ResourceNotFoundException{}),
+			cl.EXPECT().CreateSchedule(gomock.Any(), gomock.Any()).
+				Return(&scheduler.CreateScheduleOutput{
+					ScheduleArn: aws.String("arn:aws:scheduler:ap-northeast-1:99999:schedule/multi-group/daily"),
+				}, nil),
+			cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+				Name:      aws.String("hourly"),
+				GroupName: aws.String("multi-group"),
+			}).Return(nil, errors.New("err@GetSchedule")),
+		)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/multi"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.EqualError(err, `multi-group/hourly: scheduler.GetSchedule: err@GetSchedule`)
+		assert.Equal(`---
+ScheduleArn: arn:aws:scheduler:ap-northeast-1:99999:schedule/multi-group/daily
+ResultMetadata: {}
+`, out.String())
+	})
+
+	t.Run("multi-document", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetScheduleGroup(gomock.Any(),
+			&scheduler.GetScheduleGroupInput{Name: aws.String("multidoc-group")}).
+			Return(&scheduler.GetScheduleGroupOutput{Name: aws.String("multidoc-group")}, nil)
+
+		var calls []any
+		for _, name := range []string{"job-hourly", "job-daily", "job-weekly"} {
+			calls = append(calls,
+				cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+					Name:      aws.String(name),
+					GroupName: aws.String("multidoc-group"),
+				}).Return(nil, &types.ResourceNotFoundException{}),
+				cl.EXPECT().CreateSchedule(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, in *scheduler.CreateScheduleInput, _ ...func(*scheduler.Options)) (*scheduler.CreateScheduleOutput, error) {
+						assert.Equal(name, *in.Name)
+						return &scheduler.CreateScheduleOutput{
+							ScheduleArn: aws.String("arn:aws:scheduler:ap-northeast-1:99999:schedule/multidoc-group/" + name),
+						}, nil
+					}),
+			)
+		}
+		gomock.InOrder(calls...)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/multidoc/job.yml"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+		assert.Equal(`---
+ScheduleArn: arn:aws:scheduler:ap-northeast-1:99999:schedule/multidoc-group/job-hourly
+ResultMetadata: {}
+---
+ScheduleArn: arn:aws:scheduler:ap-northeast-1:99999:schedule/multidoc-group/job-daily
+ResultMetadata: {}
+---
+ScheduleArn: arn:aws:scheduler:ap-northeast-1:99999:schedule/multidoc-group/job-weekly
+ResultMetadata: {}
+`, out.String())
+	})
+
+	t.Run("err-multi-document", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/update/err-multi-document.yml"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.EqualError(err, `prepareInputSchedules: testdata/update/err-multi-document.yml: document[1]: validateScheduleYAML: [12:1] unknown field "ScheduleExpresion" in top level
+[9:1] missing required field "ScheduleExpression" in top level`)
+		assert.Equal(``, out.String())
+	})
+
+	t.Run("err-duplicated", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		b, err := os.ReadFile("testdata/multi/hourly.yml")
+		assert.NoError(err)
+		dup := filepath.Join(t.TempDir(), "dup.yml")
+		assert.NoError(os.WriteFile(dup, b, 0644))
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"update",
+			// Same file is read only once.
+			"--schedule", "testdata/multi/*.yml",
+			"--schedule", "testdata/multi/hourly.yml",
+			"--schedule", dup,
+		})
+		err = cmd.ExecuteContext(ctx)
+
+		assert.EqualError(err, fmt.Sprintf(`prepareInputSchedules: %s: schedule multi-group/hourly is already defined in testdata/multi/hourly.yml`, dup))
+		assert.Equal(``, out.String())
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetScheduleGroup(gomock.Any(), gomock.Any()).
+			Return(&scheduler.GetScheduleGroupOutput{Name: aws.String("multi-group")}, nil)
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).
+			Return(&scheduler.GetScheduleOutput{}, nil).Times(2)
+		cl.EXPECT().UpdateSchedule(gomock.Any(), gomock.Any()).
+			Return(&scheduler.UpdateScheduleOutput{}, nil).Times(2)
+
+		gomock.InOrder(
+			cl.EXPECT().ListSchedules(gomock.Any(), CmpDiff(&scheduler.ListSchedulesInput{
+				GroupName: aws.String("multi-group"),
+			}, cmpopts.IgnoreUnexported(scheduler.ListSchedulesInput{})), gomock.Any()).
+				Return(&scheduler.ListSchedulesOutput{
+					Schedules: []types.ScheduleSummary{
+						{GroupName: aws.String("multi-group"), Name: aws.String("daily")},
+						{GroupName: aws.String("multi-group"), Name: aws.String("stale")},
+					},
+					NextToken: aws.String("next"),
+				}, nil),
+			cl.EXPECT().ListSchedules(gomock.Any(), CmpDiff(&scheduler.ListSchedulesInput{
+				GroupName: aws.String("multi-group"),
+				NextToken: aws.String("next"),
+			}, cmpopts.IgnoreUnexported(scheduler.ListSchedulesInput{})), gomock.Any()).
+				Return(&scheduler.ListSchedulesOutput{
+					Schedules: []types.ScheduleSummary{
+						{GroupName: aws.String("multi-group"), Name: aws.String("hourly")},
+					},
+				}, nil),
+		)
+
+		cl.EXPECT().DeleteSchedule(gomock.Any(), CmpDiff(&scheduler.DeleteScheduleInput{
+			Name:      aws.String("stale"),
+			GroupName: aws.String("multi-group"),
+		}, cmpopts.IgnoreUnexported(scheduler.DeleteScheduleInput{}))).
+			Return(&scheduler.DeleteScheduleOutput{}, nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/multi", "--prune", "--history-dir", ""})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+	})
+
+	t.Run("prune-skipped-on-error", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetScheduleGroup(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("err@GetScheduleGroup"))
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/multi", "--prune"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.EqualError(err, `multi-group/daily: scheduler.GetScheduleGroup: err@GetScheduleGroup
+multi-group/hourly: scheduler.GetScheduleGroup: err@GetScheduleGroup`)
 		assert.Equal(``, out.String())
 	})
 
+	t.Run("dry-run", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetScheduleGroup(gomock.Any(), gomock.Any()).
+			Return(nil, &types.ResourceNotFoundException{})
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("daily"),
+			GroupName: aws.String("multi-group"),
+		}).Return(nil, &types.ResourceNotFoundException{})
+		remote := remoteDailyForTest()
+		remote.Arn = aws.String("arn:aws:scheduler:ap-northeast-1:99999:schedule/multi-group/hourly")
+		remote.Name = aws.String("hourly")
+		remote.ScheduleExpression = aws.String("cron(0 * * * ? *)")
+		remote.State = types.ScheduleStateDisabled
+		cl.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+			Name:      aws.String("hourly"),
+			GroupName: aws.String("multi-group"),
+		}).Return(remote, nil)
+		cl.EXPECT().ListSchedules(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&scheduler.ListSchedulesOutput{
+				Schedules: []types.ScheduleSummary{
+					{GroupName: aws.String("multi-group"), Name: aws.String("stale")},
+				},
+			}, nil)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/multi", "--prune", "--dry-run"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+		assert.Equal(`---
+Name: multi-group
+ClientToken: null
+Tags: null
+---
+FlexibleTimeWindow:
+  Mode: "OFF"
+  MaximumWindowInMinutes: null
+Name: daily
+ScheduleExpression: cron(0 3 * * ? *)
+Target:
+  Arn: arn:aws:lambda:ap-northeast-1:99999:function:some-func
+  RoleArn: arn:aws:iam::99999:role/some-scheduler-role
+  DeadLetterConfig: null
+  EcsParameters: null
+  EventBridgeParameters: null
+  Input: null
+  KinesisParameters: null
+  RetryPolicy: null
+  SageMakerPipelineParameters: null
+  SqsParameters: null
+ActionAfterCompletion: ""
+ClientToken: null
+Description: null
+EndDate: null
+GroupName: multi-group
+KmsKeyArn: null
+ScheduleExpressionTimezone: null
+StartDate: null
+State: ENABLED
+--- arn:aws:scheduler:ap-northeast-1:99999:schedule/multi-group/hourly
++++ testdata/multi/hourly.yml
+@@ -5,7 +5,7 @@
+ Name: hourly
+ ScheduleExpression: cron(0 * * * ? *)
+ ScheduleExpressionTimezone: UTC
+-State: DISABLED
++State: ENABLED
+ Target:
+   Arn: arn:aws:lambda:ap-northeast-1:99999:function:some-func
+   RetryPolicy:
+
+---
+FlexibleTimeWindow:
+  Mode: "OFF"
+  MaximumWindowInMinutes: null
+Name: hourly
+ScheduleExpression: cron(0 * * * ? *)
+Target:
+  Arn: arn:aws:lambda:ap-northeast-1:99999:function:some-func
+  RoleArn: arn:aws:iam::99999:role/some-scheduler-role
+  DeadLetterConfig: null
+  EcsParameters: null
+  EventBridgeParameters: null
+  Input: null
+  KinesisParameters: null
+  RetryPolicy: null
+  SageMakerPipelineParameters: null
+  SqsParameters: null
+ActionAfterCompletion: ""
+ClientToken: null
+Description: null
+EndDate: null
+GroupName: multi-group
+KmsKeyArn: null
+ScheduleExpressionTimezone: null
+StartDate: null
+State: ENABLED
+---
+Name: stale
+ClientToken: null
+GroupName: multi-group
+`, out.String())
+	})
+
+	t.Run("concurrency", func(t *testing.T) {
+		assert := assert.New(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		out := bytes.NewBuffer(nil)
+		cl := mock_ebschedule.NewMockSchedulerClient(ctrl)
+
+		cl.EXPECT().GetScheduleGroup(gomock.Any(), gomock.Any()).
+			Return(&scheduler.GetScheduleGroupOutput{Name: aws.String("multi-group")}, nil)
+		cl.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).
+			Return(nil, &types.ResourceNotFoundException{}).Times(2)
+		cl.EXPECT().CreateSchedule(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, in *scheduler.CreateScheduleInput, _ ...func(*scheduler.Options)) (*scheduler.CreateScheduleOutput, error) {
+				// daily, which comes first, finishes later.
+				if *in.Name == "daily" {
+					time.Sleep(50 * time.Millisecond)
+				}
+				return &scheduler.CreateScheduleOutput{
+					ScheduleArn: aws.String("arn:aws:scheduler:ap-northeast-1:99999:schedule/multi-group/" + *in.Name),
+				}, nil
+			}).Times(2)
+
+		cmd := NewCommand(&CommandInput{
+			AppName:         "ut",
+			Version:         "v0.0.1",
+			SchedulerClient: cl,
+			OutWriter:       out,
+		})
+		ctx := context.Background()
+		cmd.SetArgs([]string{"update", "--schedule", "testdata/multi", "--concurrency", "2", "--rate-limit", "0"})
+		err := cmd.ExecuteContext(ctx)
+
+		assert.NoError(err)
+		assert.Equal(`---
+ScheduleArn: arn:aws:scheduler:ap-northeast-1:99999:schedule/multi-group/daily
+ResultMetadata: {}
+---
+ScheduleArn: arn:aws:scheduler:ap-northeast-1:99999:schedule/multi-group/hourly
+ResultMetadata: {}
+`, out.String())
+	})
 }